@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httprate"
 	_ "github.com/lib/pq"
+	"golang.org/x/oauth2"
 
 	"github.com/yourusername/resume-builder/internal/client"
 	"github.com/yourusername/resume-builder/internal/config"
@@ -76,32 +78,95 @@ func run() error {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	resumeRepo := repository.NewResumeRepository(db)
+	themeRepo := repository.NewThemeRepository(db)
+	snapshotRepo := repository.NewSnapshotRepository(db)
+	repoRepo := repository.NewRepoRepository(db)
+	jobRepo := repository.NewJobRepository(db)
 
 	// Initialize clients
-	githubClient := client.NewGitHubClient()
-	llmClient := client.NewLLMClient(cfg.OpenAI.APIKey, cfg.OpenAI.Enabled)
-	if cfg.OpenAI.Enabled {
-		logger.Info("llm enabled for resume summaries")
+	githubEndpoints := client.GitHubEndpoints{
+		BaseURL:    cfg.GitHub.BaseURL,
+		UploadURL:  cfg.GitHub.UploadURL,
+		GraphQLURL: cfg.GitHub.GraphQLURL,
+	}
+	githubClient := client.NewGitHubClient(githubEndpoints, cfg.GitHub.MaxRepoPages, cache)
+	llmProvider, err := client.NewLLMProvider(cfg.LLM.Provider, cfg.LLM.BaseURL, cfg.LLM.APIKey, cfg.LLM.Model)
+	if err != nil {
+		return fmt.Errorf("failed to create llm provider: %w", err)
+	}
+	if cfg.LLM.FixtureDir != "" {
+		model := client.LLMModelID(cfg.LLM.Provider, cfg.LLM.Model)
+		if cfg.LLM.FixtureRecord {
+			llmProvider = client.NewFixtureProvider(cfg.LLM.FixtureDir, model, llmProvider)
+		} else {
+			llmProvider = client.NewFixtureProvider(cfg.LLM.FixtureDir, model, nil)
+		}
+		logger.Info("using llm fixtures", "dir", cfg.LLM.FixtureDir, "record", cfg.LLM.FixtureRecord)
+	}
+	llmClient := client.NewLLMClient(llmProvider, cfg.LLM.Enabled)
+	if cfg.LLM.Enabled {
+		logger.Info("llm enabled for resume summaries", "provider", cfg.LLM.Provider, "model", cfg.LLM.Model)
 	}
 
 	// Initialize services
 	jwtService := service.NewJWTService(cfg.Crypto.JWTSecret)
+	var githubAppClient *client.GitHubAppClient
+	if cfg.GitHub.AuthMode == "app" {
+		githubAppClient, err = client.NewGitHubAppClient(githubEndpoints, cfg.GitHub.AppID, cfg.GitHub.AppPrivateKey)
+		if err != nil {
+			return fmt.Errorf("failed to create github app client: %w", err)
+		}
+		logger.Info("using github app authentication", "app_id", cfg.GitHub.AppID)
+	}
 	authService := service.NewAuthService(
 		userRepo,
 		githubClient,
+		githubAppClient,
 		encryptor,
 		cfg.GitHub.ClientID,
 		cfg.GitHub.ClientSecret,
 		cfg.GitHub.RedirectURL,
+		oauth2.Endpoint{
+			AuthURL:  cfg.GitHub.AuthURL,
+			TokenURL: cfg.GitHub.TokenURL,
+		},
 	)
-	githubService := service.NewGitHubService(githubClient, cache)
+	var githubFetcher service.GitHubFetcher = githubClient
+	if cfg.GitHub.API == "graphql" {
+		githubFetcher = client.NewGitHubGraphQLClient(githubEndpoints, cfg.GitHub.MaxRepoPages, cache)
+		logger.Info("using github graphql api")
+	}
+	// Snapshots are only trusted while webhooks keep them current.
+	var snapshotMaxAge time.Duration
+	if cfg.GitHub.WebhookSecret != "" {
+		snapshotMaxAge = cfg.GitHub.SnapshotMaxAge
+	}
+	githubService := service.NewGitHubService(githubFetcher, cache, snapshotRepo, repoRepo, snapshotMaxAge)
 	rankingService := service.NewRankingService()
-	resumeService := service.NewResumeService(resumeRepo, userRepo, githubService, rankingService, llmClient)
+	resumeService := service.NewResumeService(resumeRepo, userRepo, githubService, rankingService, llmClient, cfg.GitHub.WebURL)
+	themeService := service.NewThemeService(themeRepo, cfg.GitHub.WebURL)
+	webhookService := service.NewWebhookService(snapshotRepo)
+	jobService := service.NewJobService(jobRepo, authService, resumeService, cfg.Jobs.Workers)
+	refreshService := service.NewRefreshService(
+		repository.NewAdvisoryLock(db, repository.RefreshLockKey),
+		repoRepo,
+		resumeRepo,
+		userRepo,
+		authService,
+		githubService,
+		resumeService,
+		cfg.Refresh.Interval,
+		cfg.Refresh.StaleAfter,
+		cfg.Refresh.Action == "regenerate",
+	)
 
 	// Initialize handlers
 	frontendURL := getEnv("FRONTEND_URL", "http://localhost:5173")
 	authHandler := handler.NewAuthHandler(authService, jwtService, frontendURL)
-	resumeHandler := handler.NewResumeHandler(resumeService, authService)
+	resumeHandler := handler.NewResumeHandler(resumeService, authService, themeService, jobService, cfg.GitHub.WebURL)
+	jobHandler := handler.NewJobHandler(jobService)
+	themeHandler := handler.NewThemeHandler(themeService)
+	webhookHandler := handler.NewWebhookHandler(webhookService, cfg.GitHub.WebhookSecret)
 	authMiddleware := handler.NewAuthMiddleware(jwtService, logger)
 
 	// Setup router
@@ -111,7 +176,6 @@ func run() error {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
-	r.Use(middleware.Timeout(60 * time.Second))
 	r.Use(httprate.LimitByIP(100, 1*time.Minute))
 
 	// CORS middleware
@@ -128,26 +192,56 @@ func run() error {
 		})
 	})
 
-	// Public routes
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	protectedRateLimit := httprate.LimitByIP(50, 1*time.Minute)
 
-	r.Get("/auth/login", authHandler.Login)
-	r.Get("/auth/callback", authHandler.Callback)
-	r.Get("/auth/github/callback", authHandler.Callback) // GitHub OAuth callback
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(60 * time.Second))
 
-	// Protected routes
+		// Public routes
+		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		})
+
+		r.Get("/auth/login", authHandler.Login)
+		r.Get("/auth/callback", authHandler.Callback)
+		r.Get("/auth/github/callback", authHandler.Callback) // GitHub OAuth callback
+
+		if cfg.GitHub.WebhookSecret != "" {
+			r.Post("/webhooks/github", webhookHandler.GitHub)
+		}
+
+		// Protected routes
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Use(protectedRateLimit)
+
+			r.Post("/auth/stream-token", authHandler.StreamToken)
+			r.Post("/resumes/generate", resumeHandler.Generate)
+			r.Post("/resumes/import", resumeHandler.ImportJSONResume)
+			r.Get("/resumes", resumeHandler.List)
+			r.Get("/resumes/{id}", resumeHandler.Get)
+			r.Get("/resumes/{id}/export.pdf", resumeHandler.ExportPDF)
+			r.Get("/resumes/{id}/export.jsonresume", resumeHandler.ExportJSONResume)
+			r.Get("/resumes/{id}/export.tex", resumeHandler.ExportLaTeX)
+			r.Get("/resumes/{id}/render", resumeHandler.Render)
+			r.Put("/resumes/{id}", resumeHandler.Update)
+			r.Delete("/resumes/{id}", resumeHandler.Delete)
+
+			r.Get("/jobs/{id}", jobHandler.Get)
+
+			r.Get("/themes", themeHandler.List)
+			r.Post("/themes", themeHandler.Upload)
+			r.Delete("/themes/{name}", themeHandler.Delete)
+		})
+	})
+
+	// Event streams outlive the request timeout and set their own deadline.
 	r.Group(func(r chi.Router) {
-		r.Use(authMiddleware.Authenticate)
-		r.Use(httprate.LimitByIP(50, 1*time.Minute))
-
-		r.Post("/resumes/generate", resumeHandler.Generate)
-		r.Get("/resumes", resumeHandler.List)
-		r.Get("/resumes/{id}", resumeHandler.Get)
-		r.Put("/resumes/{id}", resumeHandler.Update)
-		r.Delete("/resumes/{id}", resumeHandler.Delete)
+		r.Use(authMiddleware.AuthenticateStream)
+		r.Use(protectedRateLimit)
+
+		r.Get("/resumes/generate/stream", resumeHandler.GenerateStream)
 	})
 
 	// Start server
@@ -159,6 +253,28 @@ func run() error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Background work: generation jobs and the refresh of stale resumes
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		jobService.Run(backgroundCtx)
+	}()
+	logger.Info("generation job workers started", "workers", cfg.Jobs.Workers)
+	if cfg.Refresh.Enabled {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			refreshService.Run(backgroundCtx)
+		}()
+		logger.Info("resume refresh enabled", "interval", cfg.Refresh.Interval, "stale_after", cfg.Refresh.StaleAfter, "action", cfg.Refresh.Action)
+	}
+	defer func() {
+		stopBackground()
+		background.Wait()
+	}()
+
 	// Graceful shutdown
 	serverErrors := make(chan error, 1)
 	go func() {
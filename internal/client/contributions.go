@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// maxContributionRepos caps how many repositories are looked up per
+// contribution source, keeping the ones with the most activity.
+const maxContributionRepos = 20
+
+// GetOrganizations returns the organizations the user belongs to. Only
+// public memberships are visible to installation tokens.
+func (c *GitHubClient) GetOrganizations(ctx context.Context, token, login string) ([]string, error) {
+	var orgs []struct {
+		Login string `json:"login"`
+	}
+
+	path := "/user/orgs?per_page=100"
+	if isInstallationToken(token) {
+		path = "/users/" + login + "/orgs?per_page=100"
+	}
+
+	if err := c.doRequest(ctx, "GET", path, token, &orgs); err != nil {
+		return nil, err
+	}
+
+	logins := make([]string, len(orgs))
+	for i, org := range orgs {
+		logins[i] = org.Login
+	}
+
+	return logins, nil
+}
+
+// GetContributions finds repositories the user works on without owning them:
+// organization repositories they committed to and external projects that
+// merged their pull requests.
+func (c *GitHubClient) GetContributions(ctx context.Context, token, login string) ([]model.Contribution, error) {
+	orgs, err := c.GetOrganizations(ctx, token, login)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make(map[string]*contributionActivity)
+
+	// The commit search failing shouldn't cost the pull requests.
+	if len(orgs) > 0 {
+		if err := c.collectOrgCommits(ctx, token, login, orgs, activity); err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			slog.Warn("failed to search organization commits", "error", err, "login", login)
+		}
+	}
+
+	if err := c.collectMergedPullRequests(ctx, token, login, activity); err != nil {
+		return nil, err
+	}
+
+	return c.resolveContributions(ctx, token, activity)
+}
+
+type contributionActivity struct {
+	repoURL            string
+	kind               model.ContributionKind
+	commitCount        int
+	mergedPullRequests int
+	lastContributedAt  time.Time
+}
+
+func (a *contributionActivity) touch(at time.Time) {
+	if at.After(a.lastContributedAt) {
+		a.lastContributedAt = at
+	}
+}
+
+// collectOrgCommits searches the user's commits once, rather than once per
+// organization, since commit search has a small per-minute quota, and keeps
+// the ones in repositories owned by orgs.
+func (c *GitHubClient) collectOrgCommits(ctx context.Context, token, login string, orgs []string, activity map[string]*contributionActivity) error {
+	var result struct {
+		Items []struct {
+			Commit struct {
+				Author struct {
+					Date time.Time `json:"date"`
+				} `json:"author"`
+			} `json:"commit"`
+			Repository struct {
+				FullName string `json:"full_name"`
+				URL      string `json:"url"`
+			} `json:"repository"`
+		} `json:"items"`
+	}
+
+	query := url.QueryEscape(fmt.Sprintf("author:%s -user:%s", login, login))
+	if err := c.doRequest(ctx, "GET", "/search/commits?per_page=100&q="+query, token, &result); err != nil {
+		return err
+	}
+
+	isOrg := make(map[string]bool, len(orgs))
+	for _, org := range orgs {
+		isOrg[strings.ToLower(org)] = true
+	}
+
+	for _, item := range result.Items {
+		owner, _, _ := strings.Cut(item.Repository.FullName, "/")
+		if !isOrg[strings.ToLower(owner)] {
+			continue
+		}
+
+		a, ok := activity[item.Repository.FullName]
+		if !ok {
+			a = &contributionActivity{repoURL: item.Repository.URL, kind: model.ContributionOrganization}
+			activity[item.Repository.FullName] = a
+		}
+		a.commitCount++
+		a.touch(item.Commit.Author.Date)
+	}
+
+	return nil
+}
+
+func (c *GitHubClient) collectMergedPullRequests(ctx context.Context, token, login string, activity map[string]*contributionActivity) error {
+	var result struct {
+		Items []struct {
+			RepositoryURL string    `json:"repository_url"`
+			ClosedAt      time.Time `json:"closed_at"`
+		} `json:"items"`
+	}
+
+	query := url.QueryEscape(fmt.Sprintf("type:pr is:merged author:%s -user:%s", login, login))
+	if err := c.doRequest(ctx, "GET", "/search/issues?per_page=100&q="+query, token, &result); err != nil {
+		return err
+	}
+
+	for _, item := range result.Items {
+		fullName := strings.TrimPrefix(item.RepositoryURL, c.baseURL+"/repos/")
+		a, ok := activity[fullName]
+		if !ok {
+			a = &contributionActivity{repoURL: item.RepositoryURL, kind: model.ContributionExternal}
+			activity[fullName] = a
+		}
+		a.mergedPullRequests++
+		a.touch(item.ClosedAt)
+	}
+
+	return nil
+}
+
+// resolveContributions fetches repository details for the most active
+// entries concurrently, returning them ordered by activity.
+func (c *GitHubClient) resolveContributions(ctx context.Context, token string, activity map[string]*contributionActivity) ([]model.Contribution, error) {
+	entries := make([]*contributionActivity, 0, len(activity))
+	for _, a := range activity {
+		entries = append(entries, a)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].commitCount+entries[i].mergedPullRequests > entries[j].commitCount+entries[j].mergedPullRequests
+	})
+	if len(entries) > maxContributionRepos {
+		entries = entries[:maxContributionRepos]
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	contributions := make([]model.Contribution, len(entries))
+	errs := make([]error, len(entries))
+	sem := make(chan struct{}, maxConcurrentRequests)
+
+	var wg sync.WaitGroup
+	for i, a := range entries {
+		wg.Add(1)
+		go func(i int, a *contributionActivity) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var repo githubRepo
+			if _, err := c.do(ctx, "GET", a.repoURL, token, &repo); err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+
+			contributions[i] = model.Contribution{
+				Repository:         repo.toModel(),
+				Kind:               a.kind,
+				CommitCount:        a.commitCount,
+				MergedPullRequests: a.mergedPullRequests,
+				LastContributedAt:  a.lastContributedAt,
+			}
+		}(i, a)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return contributions, nil
+}
@@ -1,28 +1,76 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/yourusername/resume-builder/internal/model"
 )
 
+var (
+	ErrNotFound = errors.New("github resource not found")
+	// ErrStatsPending is returned while GitHub is still computing repository
+	// statistics; the request should be retried shortly.
+	ErrStatsPending = errors.New("github statistics are being computed")
+)
+
+// maxConcurrentRequests bounds how many GitHub requests a single call issues at once.
+const maxConcurrentRequests = 4
+
+// GitHubEndpoints locates the APIs of a GitHub instance, either github.com
+// or a GitHub Enterprise Server.
+type GitHubEndpoints struct {
+	BaseURL    string
+	UploadURL  string
+	GraphQLURL string
+}
+
 type GitHubClient struct {
 	httpClient *http.Client
 	baseURL    string
+	uploadURL  string
+	maxPages   int
+	rateLimits *rateLimitTracker
+	// cache stores ETag and Last-Modified validators for conditional GETs,
+	// whose 304 responses don't count against the rate limit.
+	cache *CacheClient
+	// graphql is used for data the REST API doesn't expose, like pinned items.
+	graphql *GitHubGraphQLClient
 }
 
-func NewGitHubClient() *GitHubClient {
+func NewGitHubClient(endpoints GitHubEndpoints, maxPages int, cache *CacheClient) *GitHubClient {
+	if maxPages < 1 {
+		maxPages = 1
+	}
 	return &GitHubClient{
 		httpClient: &http.Client{Timeout: 10 * time.Second},
-		baseURL:    "https://api.github.com",
+		baseURL:    endpoints.BaseURL,
+		uploadURL:  endpoints.UploadURL,
+		maxPages:   maxPages,
+		rateLimits: newRateLimitTracker(),
+		cache:      cache,
+		graphql:    NewGitHubGraphQLClient(endpoints, maxPages, cache),
 	}
 }
 
 func (c *GitHubClient) GetProfile(ctx context.Context, token string) (*model.GitHubProfile, error) {
+	return c.getProfile(ctx, "/user", token)
+}
+
+func (c *GitHubClient) getProfile(ctx context.Context, path, token string) (*model.GitHubProfile, error) {
 	var profile struct {
 		ID        int64  `json:"id"`
 		Login     string `json:"login"`
@@ -34,7 +82,7 @@ func (c *GitHubClient) GetProfile(ctx context.Context, token string) (*model.Git
 		Location  string `json:"location"`
 	}
 
-	if err := c.doRequest(ctx, "GET", "/user", token, &profile); err != nil {
+	if err := c.doRequest(ctx, "GET", path, token, &profile); err != nil {
 		return nil, err
 	}
 
@@ -50,68 +98,355 @@ func (c *GitHubClient) GetProfile(ctx context.Context, token string) (*model.Git
 	}, nil
 }
 
+// GetUserData fetches the profile, including pinned repositories, and the
+// repositories of the user. login is only used with installation tokens,
+// which aren't tied to a user.
+func (c *GitHubClient) GetUserData(ctx context.Context, token, login string) (*model.GitHubProfile, []model.Repository, error) {
+	profilePath := "/user"
+	if isInstallationToken(token) {
+		profilePath = "/users/" + login
+	}
+
+	profile, err := c.getProfile(ctx, profilePath, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Pinning only boosts ranking, so the resume can do without it.
+	profile.PinnedRepositories, err = c.graphql.GetPinnedRepositories(ctx, token, login)
+	if err != nil {
+		slog.Warn("failed to fetch pinned repositories", "error", err, "login", profile.Login)
+	}
+
+	repos, err := c.GetRepositories(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return profile, repos, nil
+}
+
+// GetLanguages returns the number of bytes of code per language in a repository.
+func (c *GitHubClient) GetLanguages(ctx context.Context, token, fullName string) (map[string]int, error) {
+	languages := make(map[string]int)
+	if err := c.doRequest(ctx, "GET", "/repos/"+fullName+"/languages", token, &languages); err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
+
+// GetManifests returns the contents of those of the given root-level files
+// that exist in a repository, keyed by path.
+func (c *GitHubClient) GetManifests(ctx context.Context, token, fullName string, paths []string) (map[string][]byte, error) {
+	var entries []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+
+	manifests := make(map[string][]byte)
+	if err := c.doRequest(ctx, "GET", "/repos/"+fullName+"/contents/", token, &entries); err != nil {
+		// Empty repositories have no contents at all.
+		if errors.Is(err, ErrNotFound) {
+			return manifests, nil
+		}
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		wanted[path] = true
+	}
+
+	for _, entry := range entries {
+		if entry.Type != "file" || !wanted[entry.Name] {
+			continue
+		}
+
+		var file githubContent
+		if err := c.doRequest(ctx, "GET", "/repos/"+fullName+"/contents/"+entry.Name, token, &file); err != nil {
+			return nil, err
+		}
+		if file.Encoding != "base64" {
+			continue
+		}
+
+		content, err := file.decode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s in %s: %w", entry.Name, fullName, err)
+		}
+		manifests[entry.Name] = content
+	}
+
+	return manifests, nil
+}
+
+// GetReadme returns the raw contents of a repository's README, or an empty
+// string if it has none.
+func (c *GitHubClient) GetReadme(ctx context.Context, token, fullName string) (string, error) {
+	var file githubContent
+	if err := c.doRequest(ctx, "GET", "/repos/"+fullName+"/readme", token, &file); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	if file.Encoding != "base64" {
+		return "", nil
+	}
+
+	content, err := file.decode()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode readme of %s: %w", fullName, err)
+	}
+
+	return string(content), nil
+}
+
+type githubContent struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+func (f githubContent) decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.ReplaceAll(f.Content, "\n", ""))
+}
+
+type githubRepo struct {
+	Name        string    `json:"name"`
+	FullName    string    `json:"full_name"`
+	Description string    `json:"description"`
+	HTMLURL     string    `json:"html_url"`
+	Stars       int       `json:"stargazers_count"`
+	Forks       int       `json:"forks_count"`
+	Language    string    `json:"language"`
+	Topics      []string  `json:"topics"`
+	Private     bool      `json:"private"`
+	Fork        bool      `json:"fork"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PushedAt    time.Time `json:"pushed_at"`
+}
+
+// githubRepoPage is a page of a repository listing: a plain array, or an
+// object wrapping the array for installation repositories.
+type githubRepoPage []githubRepo
+
+func (p *githubRepoPage) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var wrapped struct {
+			Repositories []githubRepo `json:"repositories"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return err
+		}
+		*p = wrapped.Repositories
+		return nil
+	}
+	return json.Unmarshal(data, (*[]githubRepo)(p))
+}
+
+func (r githubRepo) toModel() model.Repository {
+	return model.Repository{
+		Name:           r.Name,
+		FullName:       r.FullName,
+		Description:    r.Description,
+		URL:            r.HTMLURL,
+		Stars:          r.Stars,
+		Forks:          r.Forks,
+		Language:       r.Language,
+		Topics:         r.Topics,
+		LastCommitDate: r.PushedAt,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+		IsPrivate:      r.Private,
+		IsFork:         r.Fork,
+	}
+}
+
+// GetRepositories fetches every repository of the authenticated user, or
+// of the installation for installation tokens, up to maxPages pages of 100.
+// When GitHub reports the last page up front the remaining pages are fetched
+// concurrently, otherwise rel="next" links are followed one at a time.
 func (c *GitHubClient) GetRepositories(ctx context.Context, token string) ([]model.Repository, error) {
-	var repos []struct {
-		Name        string    `json:"name"`
-		FullName    string    `json:"full_name"`
-		Description string    `json:"description"`
-		HTMLURL     string    `json:"html_url"`
-		Stars       int       `json:"stargazers_count"`
-		Forks       int       `json:"forks_count"`
-		Language    string    `json:"language"`
-		Topics      []string  `json:"topics"`
-		Private     bool      `json:"private"`
-		Fork        bool      `json:"fork"`
-		CreatedAt   time.Time `json:"created_at"`
-		UpdatedAt   time.Time `json:"updated_at"`
-		PushedAt    time.Time `json:"pushed_at"`
-	}
-
-	if err := c.doRequest(ctx, "GET", "/user/repos?per_page=100&sort=updated", token, &repos); err != nil {
+	path := "/user/repos?per_page=100&sort=updated"
+	if isInstallationToken(token) {
+		path = "/installation/repositories?per_page=100"
+	}
+
+	var first githubRepoPage
+	header, err := c.do(ctx, "GET", c.baseURL+path, token, &first)
+	if err != nil {
 		return nil, err
 	}
 
-	result := make([]model.Repository, len(repos))
-	for i, r := range repos {
-		result[i] = model.Repository{
-			Name:           r.Name,
-			FullName:       r.FullName,
-			Description:    r.Description,
-			URL:            r.HTMLURL,
-			Stars:          r.Stars,
-			Forks:          r.Forks,
-			Language:       r.Language,
-			Topics:         r.Topics,
-			LastCommitDate: r.PushedAt,
-			CreatedAt:      r.CreatedAt,
-			UpdatedAt:      r.UpdatedAt,
-			IsPrivate:      r.Private,
-			IsFork:         r.Fork,
+	pages := []githubRepoPage{first}
+	links := parseLinkHeader(header.Get("Link"))
+
+	if last := pageNumber(links["last"]); last > 1 {
+		rest, err := c.fetchPages(ctx, path, token, 2, min(last, c.maxPages))
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, rest...)
+	} else {
+		next := links["next"]
+		for next != "" && len(pages) < c.maxPages {
+			var page githubRepoPage
+			header, err := c.do(ctx, "GET", next, token, &page)
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, page)
+			next = parseLinkHeader(header.Get("Link"))["next"]
+		}
+	}
+
+	// Pages are sorted by update time, so a repository pushed to mid-fetch
+	// can shift onto a later page and appear twice.
+	seen := make(map[string]bool)
+	var result []model.Repository
+	for _, page := range pages {
+		for _, r := range page {
+			if seen[r.FullName] {
+				continue
+			}
+			seen[r.FullName] = true
+			result = append(result, r.toModel())
 		}
 	}
 
 	return result, nil
 }
 
+// fetchPages fetches pages from..to of a paginated listing concurrently and
+// returns them in page order.
+func (c *GitHubClient) fetchPages(ctx context.Context, path, token string, from, to int) ([]githubRepoPage, error) {
+	if to < from {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([]githubRepoPage, to-from+1)
+	errs := make([]error, len(pages))
+	sem := make(chan struct{}, maxConcurrentRequests)
+
+	var wg sync.WaitGroup
+	for i := range pages {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pageURL := fmt.Sprintf("%s%s&page=%d", c.baseURL, path, from+i)
+			if _, err := c.do(ctx, "GET", pageURL, token, &pages[i]); err != nil {
+				errs[i] = err
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pages, nil
+}
+
 func (c *GitHubClient) doRequest(ctx context.Context, method, path, token string, result interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
-	if err != nil {
-		return err
+	_, err := c.do(ctx, method, c.baseURL+path, token, result)
+	return err
+}
+
+// do performs a request against an absolute URL and returns the response
+// headers alongside the decoded body. GET requests are made conditional on
+// the previously cached response, which is reused when GitHub answers 304.
+func (c *GitHubClient) do(ctx context.Context, method, rawURL, token string, result interface{}) (http.Header, error) {
+	var cacheKey string
+	var cached *cachedResponse
+	if method == http.MethodGet && c.cache != nil {
+		cacheKey = conditionalCacheKey(rawURL, token)
+		cached = loadCachedResponse(ctx, c.cache, cacheKey)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	resp, err := c.rateLimits.send(ctx, c.httpClient, token, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := c.httpClient.Do(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if cached != nil {
+			cached.setConditionalHeaders(req)
+		}
+		return req, nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	case http.StatusAccepted:
+		return nil, ErrStatsPending
+	case http.StatusNoContent:
+		return resp.Header, nil
+	case http.StatusNotModified:
+		if cached == nil {
+			return nil, fmt.Errorf("github api error: unexpected status %d", resp.StatusCode)
+		}
+		header := resp.Header.Clone()
+		header.Set("Link", cached.Link)
+		return header, json.Unmarshal(cached.Body, result)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("github api error: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("github api error: status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, err
+	}
+
+	if cacheKey != "" {
+		storeCachedResponse(ctx, c.cache, cacheKey, resp.Header, body)
+	}
+
+	return resp.Header, nil
+}
+
+var linkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="([^"]+)"`)
+
+// parseLinkHeader maps each rel in a Link header to its URL.
+func parseLinkHeader(header string) map[string]string {
+	links := make(map[string]string)
+	for _, match := range linkPattern.FindAllStringSubmatch(header, -1) {
+		links[match[2]] = match[1]
 	}
+	return links
+}
 
-	return json.NewDecoder(resp.Body).Decode(result)
+func pageNumber(link string) int {
+	if link == "" {
+		return 0
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return 0
+	}
+	page, _ := strconv.Atoi(u.Query().Get("page"))
+	return page
 }
@@ -0,0 +1,566 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// GitHubGraphQLClient fetches resume data from the GitHub GraphQL (v4) API,
+// which returns the profile, repositories, languages, pinned items and
+// contribution counts together instead of one REST call per resource.
+type GitHubGraphQLClient struct {
+	httpClient *http.Client
+	endpoint   string
+	maxPages   int
+	rateLimits *rateLimitTracker
+	// rest is used for data the GraphQL API doesn't expose, like
+	// contributor statistics.
+	rest *GitHubClient
+}
+
+// NewGitHubGraphQLClient creates a GraphQL client. GraphQL queries are POSTs
+// and can't be made conditional, so cache only serves the REST fallbacks.
+func NewGitHubGraphQLClient(endpoints GitHubEndpoints, maxPages int, cache *CacheClient) *GitHubGraphQLClient {
+	if maxPages < 1 {
+		maxPages = 1
+	}
+	return &GitHubGraphQLClient{
+		httpClient: &http.Client{Timeout: 20 * time.Second},
+		endpoint:   endpoints.GraphQLURL,
+		maxPages:   maxPages,
+		rateLimits: newRateLimitTracker(),
+		rest: &GitHubClient{
+			httpClient: &http.Client{Timeout: 10 * time.Second},
+			baseURL:    endpoints.BaseURL,
+			uploadURL:  endpoints.UploadURL,
+			maxPages:   maxPages,
+			rateLimits: newRateLimitTracker(),
+			cache:      cache,
+		},
+	}
+}
+
+const graphqlRepositoryFields = `
+	name
+	nameWithOwner
+	description
+	url
+	stargazerCount
+	forkCount
+	isPrivate
+	isFork
+	createdAt
+	updatedAt
+	pushedAt
+	primaryLanguage { name }
+	repositoryTopics(first: 20) { nodes { topic { name } } }
+	languages(first: 20, orderBy: {field: SIZE, direction: DESC}) { edges { size node { name } } }
+`
+
+const graphqlUserDataQuery = `
+query($after: String) {
+	viewer {
+		databaseId
+		login
+		name
+		email
+		avatarUrl
+		bio
+		company
+		location
+		pinnedItems(first: 6, types: REPOSITORY) {
+			nodes { ... on Repository { nameWithOwner } }
+		}
+		contributionsCollection {
+			contributionCalendar { totalContributions }
+		}
+		repositories(first: 100, after: $after, affiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER], ownerAffiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER], orderBy: {field: UPDATED_AT, direction: DESC}) {
+			pageInfo { hasNextPage endCursor }
+			nodes {` + graphqlRepositoryFields + `}
+		}
+	}
+}`
+
+const graphqlRepositoriesQuery = `
+query($after: String) {
+	viewer {
+		repositories(first: 100, after: $after, affiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER], ownerAffiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER], orderBy: {field: UPDATED_AT, direction: DESC}) {
+			pageInfo { hasNextPage endCursor }
+			nodes {` + graphqlRepositoryFields + `}
+		}
+	}
+}`
+
+const graphqlContributionsQuery = `
+query {
+	viewer {
+		login
+		contributionsCollection {
+			commitContributionsByRepository(maxRepositories: 100) {
+				repository { owner { __typename login } ` + graphqlRepositoryFields + ` }
+				contributions(first: 1, orderBy: {direction: DESC}) { totalCount nodes { occurredAt } }
+			}
+			pullRequestContributionsByRepository(maxRepositories: 100) {
+				repository { owner { __typename login } ` + graphqlRepositoryFields + ` }
+				contributions(first: 100) { nodes { pullRequest { merged mergedAt } } }
+			}
+		}
+	}
+}`
+
+// viewerQuery adapts a query on the viewer for installation tokens, which
+// have no viewer of their own: the user is looked up by login under the
+// viewer alias, so the response decodes the same way.
+func viewerQuery(query, token, login string, variables map[string]interface{}) (string, map[string]interface{}) {
+	if !isInstallationToken(token) {
+		return query, variables
+	}
+
+	query = strings.Replace(query, "viewer {", "viewer: user(login: $login) {", 1)
+	if strings.Contains(query, "query(") {
+		query = strings.Replace(query, "query(", "query($login: String!, ", 1)
+	} else {
+		query = strings.Replace(query, "query {", "query($login: String!) {", 1)
+	}
+
+	withLogin := map[string]interface{}{"login": login}
+	for name, value := range variables {
+		withLogin[name] = value
+	}
+	return query, withLogin
+}
+
+type graphqlRepo struct {
+	Name            string    `json:"name"`
+	NameWithOwner   string    `json:"nameWithOwner"`
+	Description     string    `json:"description"`
+	URL             string    `json:"url"`
+	StargazerCount  int       `json:"stargazerCount"`
+	ForkCount       int       `json:"forkCount"`
+	IsPrivate       bool      `json:"isPrivate"`
+	IsFork          bool      `json:"isFork"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	PushedAt        time.Time `json:"pushedAt"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	Languages struct {
+		Edges []struct {
+			Size int `json:"size"`
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"languages"`
+}
+
+func (r graphqlRepo) toModel() model.Repository {
+	repo := model.Repository{
+		Name:           r.Name,
+		FullName:       r.NameWithOwner,
+		Description:    r.Description,
+		URL:            r.URL,
+		Stars:          r.StargazerCount,
+		Forks:          r.ForkCount,
+		LastCommitDate: r.PushedAt,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+		IsPrivate:      r.IsPrivate,
+		IsFork:         r.IsFork,
+		Topics:         []string{},
+		Languages:      make(map[string]int, len(r.Languages.Edges)),
+	}
+
+	if r.PrimaryLanguage != nil {
+		repo.Language = r.PrimaryLanguage.Name
+	}
+	for _, node := range r.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, node.Topic.Name)
+	}
+	for _, edge := range r.Languages.Edges {
+		repo.Languages[edge.Node.Name] = edge.Size
+	}
+
+	return repo
+}
+
+type graphqlRepoConnection struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []graphqlRepo `json:"nodes"`
+}
+
+// GetUserData fetches the profile and the first page of repositories in one
+// query, then pages through the remaining repositories. login is only used
+// with installation tokens, which aren't tied to a user.
+func (c *GitHubGraphQLClient) GetUserData(ctx context.Context, token, login string) (*model.GitHubProfile, []model.Repository, error) {
+	var data struct {
+		Viewer struct {
+			DatabaseID  int64  `json:"databaseId"`
+			Login       string `json:"login"`
+			Name        string `json:"name"`
+			Email       string `json:"email"`
+			AvatarURL   string `json:"avatarUrl"`
+			Bio         string `json:"bio"`
+			Company     string `json:"company"`
+			Location    string `json:"location"`
+			PinnedItems struct {
+				Nodes []struct {
+					NameWithOwner string `json:"nameWithOwner"`
+				} `json:"nodes"`
+			} `json:"pinnedItems"`
+			ContributionsCollection struct {
+				ContributionCalendar struct {
+					TotalContributions int `json:"totalContributions"`
+				} `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+			Repositories graphqlRepoConnection `json:"repositories"`
+		} `json:"viewer"`
+	}
+
+	query, variables := viewerQuery(graphqlUserDataQuery, token, login, map[string]interface{}{"after": nil})
+	if err := c.query(ctx, token, query, variables, &data); err != nil {
+		return nil, nil, err
+	}
+
+	viewer := data.Viewer
+	profile := &model.GitHubProfile{
+		ID:                 viewer.DatabaseID,
+		Login:              viewer.Login,
+		Name:               viewer.Name,
+		Email:              viewer.Email,
+		AvatarURL:          viewer.AvatarURL,
+		Bio:                viewer.Bio,
+		Company:            viewer.Company,
+		Location:           viewer.Location,
+		TotalContributions: viewer.ContributionsCollection.ContributionCalendar.TotalContributions,
+	}
+	for _, node := range viewer.PinnedItems.Nodes {
+		profile.PinnedRepositories = append(profile.PinnedRepositories, node.NameWithOwner)
+	}
+
+	var repos []model.Repository
+	page := viewer.Repositories
+	for pages := 1; ; pages++ {
+		for _, r := range page.Nodes {
+			repos = append(repos, r.toModel())
+		}
+		if !page.PageInfo.HasNextPage || pages >= c.maxPages {
+			break
+		}
+
+		var next struct {
+			Viewer struct {
+				Repositories graphqlRepoConnection `json:"repositories"`
+			} `json:"viewer"`
+		}
+		query, variables := viewerQuery(graphqlRepositoriesQuery, token, login, map[string]interface{}{"after": page.PageInfo.EndCursor})
+		if err := c.query(ctx, token, query, variables, &next); err != nil {
+			return nil, nil, err
+		}
+		page = next.Viewer.Repositories
+	}
+
+	return profile, repos, nil
+}
+
+const graphqlPinnedQuery = `
+query {
+	viewer {
+		pinnedItems(first: 6, types: REPOSITORY) {
+			nodes { ... on Repository { nameWithOwner } }
+		}
+	}
+}`
+
+// GetPinnedRepositories returns the full names of the repositories pinned to
+// the user's profile, in display order.
+func (c *GitHubGraphQLClient) GetPinnedRepositories(ctx context.Context, token, login string) ([]string, error) {
+	var data struct {
+		Viewer struct {
+			PinnedItems struct {
+				Nodes []struct {
+					NameWithOwner string `json:"nameWithOwner"`
+				} `json:"nodes"`
+			} `json:"pinnedItems"`
+		} `json:"viewer"`
+	}
+
+	query, variables := viewerQuery(graphqlPinnedQuery, token, login, nil)
+	if err := c.query(ctx, token, query, variables, &data); err != nil {
+		return nil, err
+	}
+
+	pinned := make([]string, 0, len(data.Viewer.PinnedItems.Nodes))
+	for _, node := range data.Viewer.PinnedItems.Nodes {
+		pinned = append(pinned, node.NameWithOwner)
+	}
+
+	return pinned, nil
+}
+
+const graphqlLanguagesQuery = `
+query($owner: String!, $name: String!) {
+	repository(owner: $owner, name: $name) {
+		languages(first: 20, orderBy: {field: SIZE, direction: DESC}) { edges { size node { name } } }
+	}
+}`
+
+// GetLanguages returns the number of bytes of code per language in a
+// repository. Repositories from GetUserData already carry this.
+func (c *GitHubGraphQLClient) GetLanguages(ctx context.Context, token, fullName string) (map[string]int, error) {
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repository name %q", fullName)
+	}
+
+	var data struct {
+		Repository graphqlRepo `json:"repository"`
+	}
+	if err := c.query(ctx, token, graphqlLanguagesQuery, map[string]interface{}{"owner": owner, "name": name}, &data); err != nil {
+		return nil, err
+	}
+
+	return data.Repository.toModel().Languages, nil
+}
+
+// GetManifests returns the contents of those of the given root-level files
+// that exist in a repository, fetched with a single aliased query.
+func (c *GitHubGraphQLClient) GetManifests(ctx context.Context, token, fullName string, paths []string) (map[string][]byte, error) {
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repository name %q", fullName)
+	}
+
+	var fields strings.Builder
+	for i, path := range paths {
+		fmt.Fprintf(&fields, "f%d: object(expression: %q) { ... on Blob { text isBinary } }\n", i, "HEAD:"+path)
+	}
+	query := "query($owner: String!, $name: String!) { repository(owner: $owner, name: $name) {\n" + fields.String() + "} }"
+
+	var data struct {
+		Repository map[string]*struct {
+			Text     string `json:"text"`
+			IsBinary bool   `json:"isBinary"`
+		} `json:"repository"`
+	}
+	if err := c.query(ctx, token, query, map[string]interface{}{"owner": owner, "name": name}, &data); err != nil {
+		return nil, err
+	}
+
+	manifests := make(map[string][]byte)
+	for i, path := range paths {
+		if blob := data.Repository[fmt.Sprintf("f%d", i)]; blob != nil && !blob.IsBinary {
+			manifests[path] = []byte(blob.Text)
+		}
+	}
+
+	return manifests, nil
+}
+
+// readmeFiles are the README names tried, in order, by GetReadme.
+var readmeFiles = []string{"README.md", "readme.md", "Readme.md", "README.markdown", "README.rst", "README.txt", "README"}
+
+// GetReadme returns the raw contents of a repository's README, or an empty
+// string if it has none.
+func (c *GitHubGraphQLClient) GetReadme(ctx context.Context, token, fullName string) (string, error) {
+	files, err := c.GetManifests(ctx, token, fullName, readmeFiles)
+	if err != nil {
+		return "", err
+	}
+
+	for _, path := range readmeFiles {
+		if content, ok := files[path]; ok {
+			return string(content), nil
+		}
+	}
+
+	return "", nil
+}
+
+// GetAuthorship returns the user's share of a repository's commit history.
+func (c *GitHubGraphQLClient) GetAuthorship(ctx context.Context, token, fullName, login string) (*model.Authorship, error) {
+	return c.rest.GetAuthorship(ctx, token, fullName, login)
+}
+
+type graphqlContributionRepo struct {
+	graphqlRepo
+	Owner struct {
+		Typename string `json:"__typename"`
+		Login    string `json:"login"`
+	} `json:"owner"`
+}
+
+// GetContributions uses the past year's contributions collection to find
+// repositories the user committed to or had pull requests merged into
+// without owning them.
+func (c *GitHubGraphQLClient) GetContributions(ctx context.Context, token, login string) ([]model.Contribution, error) {
+	var data struct {
+		Viewer struct {
+			ContributionsCollection struct {
+				CommitContributionsByRepository []struct {
+					Repository    graphqlContributionRepo `json:"repository"`
+					Contributions struct {
+						TotalCount int `json:"totalCount"`
+						Nodes      []struct {
+							OccurredAt time.Time `json:"occurredAt"`
+						} `json:"nodes"`
+					} `json:"contributions"`
+				} `json:"commitContributionsByRepository"`
+				PullRequestContributionsByRepository []struct {
+					Repository    graphqlContributionRepo `json:"repository"`
+					Contributions struct {
+						Nodes []struct {
+							PullRequest struct {
+								Merged   bool      `json:"merged"`
+								MergedAt time.Time `json:"mergedAt"`
+							} `json:"pullRequest"`
+						} `json:"nodes"`
+					} `json:"contributions"`
+				} `json:"pullRequestContributionsByRepository"`
+			} `json:"contributionsCollection"`
+		} `json:"viewer"`
+	}
+
+	query, variables := viewerQuery(graphqlContributionsQuery, token, login, nil)
+	if err := c.query(ctx, token, query, variables, &data); err != nil {
+		return nil, err
+	}
+
+	byRepo := make(map[string]*model.Contribution)
+	get := func(r graphqlContributionRepo) *model.Contribution {
+		if contribution, ok := byRepo[r.NameWithOwner]; ok {
+			return contribution
+		}
+		kind := model.ContributionExternal
+		if r.Owner.Typename == "Organization" {
+			kind = model.ContributionOrganization
+		}
+		contribution := &model.Contribution{Repository: r.toModel(), Kind: kind}
+		byRepo[r.NameWithOwner] = contribution
+		return contribution
+	}
+
+	collection := data.Viewer.ContributionsCollection
+	for _, entry := range collection.CommitContributionsByRepository {
+		if strings.EqualFold(entry.Repository.Owner.Login, login) {
+			continue
+		}
+		contribution := get(entry.Repository)
+		contribution.CommitCount += entry.Contributions.TotalCount
+		for _, node := range entry.Contributions.Nodes {
+			if node.OccurredAt.After(contribution.LastContributedAt) {
+				contribution.LastContributedAt = node.OccurredAt
+			}
+		}
+	}
+
+	for _, entry := range collection.PullRequestContributionsByRepository {
+		if strings.EqualFold(entry.Repository.Owner.Login, login) {
+			continue
+		}
+		var merged int
+		var lastMerged time.Time
+		for _, node := range entry.Contributions.Nodes {
+			if node.PullRequest.Merged {
+				merged++
+				if node.PullRequest.MergedAt.After(lastMerged) {
+					lastMerged = node.PullRequest.MergedAt
+				}
+			}
+		}
+		if merged == 0 {
+			continue
+		}
+		contribution := get(entry.Repository)
+		contribution.MergedPullRequests += merged
+		if lastMerged.After(contribution.LastContributedAt) {
+			contribution.LastContributedAt = lastMerged
+		}
+	}
+
+	contributions := make([]model.Contribution, 0, len(byRepo))
+	for _, contribution := range byRepo {
+		contributions = append(contributions, *contribution)
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		return contributions[i].CommitCount+contributions[i].MergedPullRequests > contributions[j].CommitCount+contributions[j].MergedPullRequests
+	})
+	if len(contributions) > maxContributionRepos {
+		contributions = contributions[:maxContributionRepos]
+	}
+
+	return contributions, nil
+}
+
+func (c *GitHubGraphQLClient) query(ctx context.Context, token, query string, variables map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.rateLimits.send(ctx, c.httpClient, token, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github graphql error: status %d", resp.StatusCode)
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+
+	// An exhausted GraphQL point budget is reported in the body of a 200.
+	for _, e := range envelope.Errors {
+		if e.Type == "RATE_LIMITED" {
+			status, _ := parseRateLimitHeaders(resp.Header)
+			return &RateLimitError{Limit: status.Limit, Remaining: 0, Reset: status.Reset}
+		}
+	}
+
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("github graphql error: %s", envelope.Errors[0].Message)
+	}
+
+	return json.Unmarshal(envelope.Data, result)
+}
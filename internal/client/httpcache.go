@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// conditionalCacheTTL is how long validators and bodies are kept. Entries are
+// revalidated on every use, so this only bounds how long an unused one
+// lingers.
+const conditionalCacheTTL = 7 * 24 * time.Hour
+
+// cachedResponse is a GET response stored with its validators, so that the
+// next request for the same URL can be made conditional.
+type cachedResponse struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// Link is kept for paginated listings.
+	Link string          `json:"link,omitempty"`
+	Body json.RawMessage `json:"body"`
+}
+
+// conditionalCacheKey scopes entries to the token as well as the URL, since
+// the same URL returns different data for different users.
+func conditionalCacheKey(rawURL, token string) string {
+	return "github:http:" + tokenHash(token) + ":" + rawURL
+}
+
+// tokenHash identifies a token in keys without keeping the token itself.
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+func loadCachedResponse(ctx context.Context, cache *CacheClient, key string) *cachedResponse {
+	if cache == nil {
+		return nil
+	}
+
+	data, err := cache.Get(ctx, key)
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedResponse
+	if json.Unmarshal([]byte(data), &cached) != nil {
+		return nil
+	}
+	return &cached
+}
+
+// storeCachedResponse saves body under key if the response carries a
+// validator to revalidate it with.
+func storeCachedResponse(ctx context.Context, cache *CacheClient, key string, header http.Header, body []byte) {
+	if cache == nil {
+		return
+	}
+
+	cached := cachedResponse{
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		Link:         header.Get("Link"),
+		Body:         body,
+	}
+	if cached.ETag == "" && cached.LastModified == "" {
+		return
+	}
+
+	if data, err := json.Marshal(cached); err == nil {
+		cache.Set(ctx, key, string(data), conditionalCacheTTL)
+	}
+}
+
+func (c *cachedResponse) setConditionalHeaders(req *http.Request) {
+	if c.ETag != "" {
+		req.Header.Set("If-None-Match", c.ETag)
+	}
+	if c.LastModified != "" {
+		req.Header.Set("If-Modified-Since", c.LastModified)
+	}
+}
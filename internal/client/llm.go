@@ -5,164 +5,209 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
-type LLMClient struct {
-	apiKey  string
-	baseURL string
-	client  *http.Client
-	enabled bool
+// LLMProvider sends a single-turn completion request to a model backend and
+// returns the generated text.
+type LLMProvider interface {
+	Complete(ctx context.Context, req CompletionRequest) (string, error)
 }
 
-func NewLLMClient(apiKey string, enabled bool) *LLMClient {
-	return &LLMClient{
-		apiKey:  apiKey,
-		baseURL: "https://api.openai.com/v1/chat/completions",
-		client:  &http.Client{Timeout: 30 * time.Second},
-		enabled: enabled,
-	}
+type CompletionRequest struct {
+	System      string
+	Prompt      string
+	MaxTokens   int
+	Temperature float64
+	// JSON asks for a JSON object as the response, for backends that can
+	// enforce it.
+	JSON bool
+	// Input holds the values the prompt was built from, a SummaryInput or a
+	// ProjectInput. Model backends ignore it; FakeProvider answers from it.
+	Input interface{}
 }
 
-func (c *LLMClient) GenerateSummary(ctx context.Context, targetRole string, repoCount int, skills []string) (string, error) {
-	if !c.enabled || c.apiKey == "" {
-		return "", fmt.Errorf("llm not enabled")
-	}
-
-	prompt := fmt.Sprintf(
-		"Write a professional 3-5 sentence resume summary for a %s position. The candidate has %d GitHub repositories showcasing expertise in: %v. Highlight technical depth, impact, and career goals. Be specific and achievement-oriented.",
-		targetRole, repoCount, skills[:min(5, len(skills))],
-	)
+type SummaryInput struct {
+	TargetRole string
+	RepoCount  int
+	Skills     []string
+}
 
-	reqBody := map[string]interface{}{
-		"model": "gpt-3.5-turbo",
-		"messages": []map[string]string{
-			{"role": "system", "content": "You are an expert resume writer. Write compelling, achievement-focused summaries that highlight technical expertise and career impact."},
-			{"role": "user", "content": prompt},
-		},
-		"max_tokens":  200,
-		"temperature": 0.8,
-	}
+type ProjectInput struct {
+	Name        string
+	Description string
+	Language    string
+	Topics      []string
+	Readme      string
+}
 
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", err
+// NewLLMProvider returns the provider named by provider: "openai" for OpenAI
+// and OpenAI-compatible chat completion endpoints, "anthropic", "ollama", or
+// "fake" for the offline FakeProvider. Empty baseURL and model select the
+// provider's defaults.
+func NewLLMProvider(provider, baseURL, apiKey, model string) (LLMProvider, error) {
+	switch provider {
+	case "openai":
+		return NewOpenAIProvider(baseURL, apiKey, model), nil
+	case "anthropic":
+		return NewAnthropicProvider(baseURL, apiKey, model), nil
+	case "ollama":
+		return NewOllamaProvider(baseURL, model), nil
+	case "fake":
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q", provider)
 	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(body))
-	if err != nil {
-		return "", err
-	}
+// LLMModelID names the model NewLLMProvider selects for provider and model,
+// as "<provider>/<model>", filling in the provider's default model.
+func LLMModelID(provider, model string) string {
+	if model == "" {
+		switch provider {
+		case "openai":
+			model = defaultOpenAIModel
+		case "anthropic":
+			model = defaultAnthropicModel
+		case "ollama":
+			model = defaultOllamaModel
+		}
+	}
+	return provider + "/" + model
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+type LLMClient struct {
+	provider LLMProvider
+	enabled  bool
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return "", err
+func NewLLMClient(provider LLMProvider, enabled bool) *LLMClient {
+	return &LLMClient{
+		provider: provider,
+		enabled:  enabled,
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("openai api error: status %d", resp.StatusCode)
+func (c *LLMClient) GenerateSummary(ctx context.Context, targetRole string, repoCount int, skills []string) (string, error) {
+	if !c.enabled || c.provider == nil {
+		return "", fmt.Errorf("llm not enabled")
 	}
 
-	var result struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-	}
+	skills = skills[:min(5, len(skills))]
+	prompt := fmt.Sprintf(
+		"Write a professional 3-5 sentence resume summary for a %s position. The candidate has %d GitHub repositories showcasing expertise in: %s. Highlight technical depth, impact, and career goals. Be specific and achievement-oriented.",
+		targetRole, repoCount, strings.Join(skills, ", "),
+	)
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	summary, err := c.provider.Complete(ctx, CompletionRequest{
+		System:      "You are an expert resume writer. Write compelling, achievement-focused summaries that highlight technical expertise and career impact.",
+		Prompt:      prompt,
+		MaxTokens:   200,
+		Temperature: 0.8,
+		Input:       SummaryInput{TargetRole: targetRole, RepoCount: repoCount, Skills: skills},
+	})
+	if err != nil {
 		return "", err
 	}
 
-	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("no response from llm")
-	}
-
-	return result.Choices[0].Message.Content, nil
+	return strings.TrimSpace(summary), nil
 }
 
-func (c *LLMClient) EnhanceProjectDescription(ctx context.Context, repoName, description, language string, topics []string) (string, []string, error) {
-	if !c.enabled || c.apiKey == "" {
+// EnhanceProjectDescription rewrites a project's description and highlights.
+// readme is an optional plain-text excerpt of the project's README, which
+// grounds the result when the description is short or missing.
+func (c *LLMClient) EnhanceProjectDescription(ctx context.Context, repoName, description, language string, topics []string, readme string) (string, []string, error) {
+	if !c.enabled || c.provider == nil {
 		return description, []string{}, fmt.Errorf("llm not enabled")
 	}
 
 	prompt := fmt.Sprintf(
-		"Project: %s\nLanguage: %s\nTopics: %v\nOriginal description: %s\n\nWrite a professional 1-sentence project description and 2-3 bullet points highlighting technical achievements, impact, or key features. Format as JSON: {\"description\": \"...\", \"highlights\": [\"...\", \"...\"]}",
+		"Project: %s\nLanguage: %s\nTopics: %v\nOriginal description: %s\n",
 		repoName, language, topics, description,
 	)
-
-	reqBody := map[string]interface{}{
-		"model": "gpt-3.5-turbo",
-		"messages": []map[string]string{
-			{"role": "system", "content": "You are a technical resume writer. Create compelling project descriptions that highlight technical skills and impact. Always respond with valid JSON."},
-			{"role": "user", "content": prompt},
+	if readme != "" {
+		prompt += "README excerpt:\n" + readme + "\n"
+	}
+	prompt += "\nWrite a professional 1-sentence project description and 2-3 bullet points highlighting technical achievements, impact, or key features. Only state what the information above supports. Format as JSON: {\"description\": \"...\", \"highlights\": [\"...\", \"...\"]}"
+
+	content, err := c.provider.Complete(ctx, CompletionRequest{
+		System:      "You are a technical resume writer. Create compelling project descriptions that highlight technical skills and impact. Always respond with valid JSON.",
+		Prompt:      prompt,
+		MaxTokens:   250,
+		Temperature: 0.7,
+		JSON:        true,
+		Input: ProjectInput{
+			Name:        repoName,
+			Description: description,
+			Language:    language,
+			Topics:      topics,
+			Readme:      readme,
 		},
-		"max_tokens":  250,
-		"temperature": 0.7,
-	}
-
-	body, err := json.Marshal(reqBody)
+	})
 	if err != nil {
 		return description, []string{}, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(body))
-	if err != nil {
-		return description, []string{}, err
+	// Parse the JSON response
+	var enhanced struct {
+		Description string   `json:"description"`
+		Highlights  []string `json:"highlights"`
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.client.Do(req)
-	if err != nil {
+	if err := json.Unmarshal([]byte(extractJSONObject(content)), &enhanced); err != nil {
+		// If parsing fails, return original
 		return description, []string{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return description, []string{}, fmt.Errorf("openai api error: status %d", resp.StatusCode)
+	return enhanced.Description, enhanced.Highlights, nil
+}
+
+// extractJSONObject returns the outermost JSON object in s, dropping the
+// prose or Markdown code fences some models wrap it in.
+func extractJSONObject(s string) string {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end < start {
+		return s
 	}
+	return s[start : end+1]
+}
 
-	var result struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
+// postJSON sends body as JSON to url and decodes the JSON response into
+// result. provider names the backend in errors.
+func postJSON(ctx context.Context, httpClient *http.Client, provider, url string, header http.Header, body, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return description, []string{}, err
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(data))
+	if err != nil {
+		return err
 	}
 
-	if len(result.Choices) == 0 {
-		return description, []string{}, fmt.Errorf("no response from llm")
+	for key, values := range header {
+		req.Header[key] = values
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	// Parse the JSON response
-	var enhanced struct {
-		Description string   `json:"description"`
-		Highlights  []string `json:"highlights"`
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
-	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &enhanced); err != nil {
-		// If parsing fails, return original
-		return description, []string{}, err
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("%s api error: status %d", provider, resp.StatusCode)
 	}
 
-	return enhanced.Description, enhanced.Highlights, nil
+	return json.NewDecoder(resp.Body).Decode(result)
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+func newLLMHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
 }
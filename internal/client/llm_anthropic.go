@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	defaultAnthropicModel   = "claude-3-5-haiku-latest"
+	anthropicVersion        = "2023-06-01"
+)
+
+// AnthropicProvider calls the Anthropic Messages API.
+type AnthropicProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewAnthropicProvider(baseURL, apiKey, model string) *AnthropicProvider {
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+
+	return &AnthropicProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  newLLMHTTPClient(),
+	}
+}
+
+func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	messages := []map[string]string{
+		{"role": "user", "content": req.Prompt},
+	}
+	// The Messages API has no JSON mode, so the reply is started with the
+	// opening brace of an object for the model to continue.
+	var prefill string
+	if req.JSON {
+		prefill = "{"
+		messages = append(messages, map[string]string{"role": "assistant", "content": prefill})
+	}
+
+	reqBody := map[string]interface{}{
+		"model":       p.model,
+		"system":      req.System,
+		"messages":    messages,
+		"max_tokens":  req.MaxTokens,
+		"temperature": req.Temperature,
+	}
+
+	header := http.Header{}
+	header.Set("x-api-key", p.apiKey)
+	header.Set("anthropic-version", anthropicVersion)
+
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+
+	if err := postJSON(ctx, p.client, "anthropic", p.baseURL+"/v1/messages", header, reqBody, &result); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no response from llm")
+	}
+
+	return prefill + text.String(), nil
+}
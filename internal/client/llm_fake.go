@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FakeProvider answers without a model, deterministically deriving its
+// responses from the request's Input: summaries from the target role,
+// repository count and skills, and project enhancements, as valid JSON, from
+// the project's name, language, topics and description. It is meant for tests
+// and demos without network access.
+type FakeProvider struct{}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (p *FakeProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	switch input := req.Input.(type) {
+	case SummaryInput:
+		return fakeSummary(input), nil
+	case ProjectInput:
+		return fakeProjectEnhancement(input)
+	default:
+		return "Response " + fixtureHash("fake", req)[:8] + ".", nil
+	}
+}
+
+func fakeSummary(input SummaryInput) string {
+	repositories := "repositories"
+	if input.RepoCount == 1 {
+		repositories = "repository"
+	}
+
+	summary := fmt.Sprintf("Software engineer with %d GitHub %s.", input.RepoCount, repositories)
+	if role := strings.TrimSpace(input.TargetRole); role != "" {
+		summary = fmt.Sprintf("%s candidate with %d GitHub %s.", role, input.RepoCount, repositories)
+	}
+	if len(input.Skills) > 0 {
+		summary += " Experienced in " + strings.Join(input.Skills, ", ") + "."
+	}
+	return summary
+}
+
+func fakeProjectEnhancement(input ProjectInput) (string, error) {
+	language := strings.TrimSpace(input.Language)
+
+	description := strings.TrimSpace(input.Description)
+	switch {
+	case description != "":
+	case language != "":
+		description = "A " + language + " project"
+	default:
+		description = "A software project"
+	}
+	description = strings.TrimSuffix(description, ".") + "."
+
+	highlight := "Built " + input.Name
+	if language != "" {
+		highlight += " in " + language
+	}
+	highlights := []string{highlight}
+	if len(input.Topics) > 0 {
+		highlights = append(highlights, "Covers "+strings.Join(input.Topics, ", "))
+	}
+	if input.Readme != "" {
+		highlights = append(highlights, "Documented with a README")
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"description": description,
+		"highlights":  highlights,
+	})
+	return string(data), err
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFakeProviderSummary(t *testing.T) {
+	llm := NewLLMClient(NewFakeProvider(), true)
+
+	tests := []struct {
+		name   string
+		role   string
+		count  int
+		skills []string
+		want   string
+	}{
+		{
+			name:   "role and skills",
+			role:   "Backend Engineer",
+			count:  12,
+			skills: []string{"Go", "PostgreSQL", "Redis", "Docker", "Kubernetes", "Terraform"},
+			want:   "Backend Engineer candidate with 12 GitHub repositories. Experienced in Go, PostgreSQL, Redis, Docker, Kubernetes.",
+		},
+		{
+			name:  "no role or skills",
+			count: 1,
+			want:  "Software engineer with 1 GitHub repository.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := llm.GenerateSummary(context.Background(), tt.role, tt.count, tt.skills)
+			if err != nil {
+				t.Fatalf("GenerateSummary: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateSummary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeProviderProjectEnhancement(t *testing.T) {
+	llm := NewLLMClient(NewFakeProvider(), true)
+
+	tests := []struct {
+		name            string
+		description     string
+		language        string
+		topics          []string
+		readme          string
+		wantDescription string
+		wantHighlights  []string
+	}{
+		{
+			name:            "all fields",
+			description:     "Builds resumes from GitHub",
+			language:        "Go",
+			topics:          []string{"resume", "github"},
+			readme:          "Usage instructions",
+			wantDescription: "Builds resumes from GitHub.",
+			wantHighlights:  []string{"Built resume-builder in Go", "Covers resume, github", "Documented with a README"},
+		},
+		{
+			name:            "language only",
+			language:        "Rust",
+			wantDescription: "A Rust project.",
+			wantHighlights:  []string{"Built resume-builder in Rust"},
+		},
+		{
+			name:            "no fields",
+			wantDescription: "A software project.",
+			wantHighlights:  []string{"Built resume-builder"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			description, highlights, err := llm.EnhanceProjectDescription(context.Background(), "resume-builder", tt.description, tt.language, tt.topics, tt.readme)
+			if err != nil {
+				t.Fatalf("EnhanceProjectDescription: %v", err)
+			}
+			if description != tt.wantDescription {
+				t.Errorf("description = %q, want %q", description, tt.wantDescription)
+			}
+			if !reflect.DeepEqual(highlights, tt.wantHighlights) {
+				t.Errorf("highlights = %q, want %q", highlights, tt.wantHighlights)
+			}
+		})
+	}
+}
+
+func TestFixtureProviderRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	skills := []string{"Go", "SQL"}
+
+	recorder := NewLLMClient(NewFixtureProvider(dir, LLMModelID("fake", ""), NewFakeProvider()), true)
+	recordedSummary, err := recorder.GenerateSummary(ctx, "Backend Engineer", 3, skills)
+	if err != nil {
+		t.Fatalf("recording summary: %v", err)
+	}
+	recordedDescription, recordedHighlights, err := recorder.EnhanceProjectDescription(ctx, "resume-builder", "", "Go", nil, "")
+	if err != nil {
+		t.Fatalf("recording project: %v", err)
+	}
+
+	replayer := NewLLMClient(NewFixtureProvider(dir, LLMModelID("fake", ""), nil), true)
+	summary, err := replayer.GenerateSummary(ctx, "Backend Engineer", 3, skills)
+	if err != nil {
+		t.Fatalf("replaying summary: %v", err)
+	}
+	if summary != recordedSummary {
+		t.Errorf("replayed summary = %q, want %q", summary, recordedSummary)
+	}
+	description, highlights, err := replayer.EnhanceProjectDescription(ctx, "resume-builder", "", "Go", nil, "")
+	if err != nil {
+		t.Fatalf("replaying project: %v", err)
+	}
+	if description != recordedDescription || !reflect.DeepEqual(highlights, recordedHighlights) {
+		t.Errorf("replayed project = %q %q, want %q %q", description, highlights, recordedDescription, recordedHighlights)
+	}
+
+	if _, err := replayer.GenerateSummary(ctx, "Frontend Engineer", 3, skills); !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("unrecorded summary error = %v, want ErrFixtureNotFound", err)
+	}
+
+	otherModel := NewLLMClient(NewFixtureProvider(dir, LLMModelID("openai", ""), nil), true)
+	if _, err := otherModel.GenerateSummary(ctx, "Backend Engineer", 3, skills); !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("summary recorded for another model error = %v, want ErrFixtureNotFound", err)
+	}
+}
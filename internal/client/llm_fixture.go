@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var ErrFixtureNotFound = errors.New("llm fixture not found")
+
+// FixtureProvider replays recorded responses from a directory holding one
+// <request hash>.json file per model and request. With a recording provider,
+// requests without a fixture are passed on to it and its responses recorded;
+// otherwise they fail with ErrFixtureNotFound.
+type FixtureProvider struct {
+	dir    string
+	model  string
+	record LLMProvider
+}
+
+type llmFixture struct {
+	Model       string  `json:"model"`
+	System      string  `json:"system"`
+	Prompt      string  `json:"prompt"`
+	MaxTokens   int     `json:"max_tokens"`
+	Temperature float64 `json:"temperature"`
+	JSON        bool    `json:"json"`
+	Response    string  `json:"response"`
+}
+
+// NewFixtureProvider replays the fixtures in dir recorded for model, as named
+// by LLMModelID. record may be nil to only replay.
+func NewFixtureProvider(dir, model string, record LLMProvider) *FixtureProvider {
+	return &FixtureProvider{dir: dir, model: model, record: record}
+}
+
+func (p *FixtureProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	path := filepath.Join(p.dir, fixtureHash(p.model, req)+".json")
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		var fixture llmFixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return "", fmt.Errorf("invalid llm fixture %s: %w", path, err)
+		}
+		return fixture.Response, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	if p.record == nil {
+		return "", fmt.Errorf("%w: %s", ErrFixtureNotFound, path)
+	}
+
+	response, err := p.record.Complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	data, err = json.MarshalIndent(llmFixture{
+		Model:       p.model,
+		System:      req.System,
+		Prompt:      req.Prompt,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		JSON:        req.JSON,
+		Response:    response,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to record llm fixture: %w", err)
+	}
+
+	return response, nil
+}
+
+// fixtureHash identifies a completion request to model by everything that
+// shapes its response: the prompts and the sampling parameters.
+func fixtureHash(model string, req CompletionRequest) string {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%g\x00%t", model, req.System, req.Prompt, req.MaxTokens, req.Temperature, req.JSON)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
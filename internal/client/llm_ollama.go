@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOllamaBaseURL = "http://localhost:11434"
+	defaultOllamaModel   = "llama3.1"
+)
+
+// OllamaProvider calls the chat API of a local Ollama server.
+type OllamaProvider struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+func NewOllamaProvider(baseURL, model string) *OllamaProvider {
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+	if model == "" {
+		model = defaultOllamaModel
+	}
+
+	return &OllamaProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   model,
+		client:  newLLMHTTPClient(),
+	}
+}
+
+func (p *OllamaProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	reqBody := map[string]interface{}{
+		"model": p.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": req.System},
+			{"role": "user", "content": req.Prompt},
+		},
+		"stream": false,
+		"options": map[string]interface{}{
+			"num_predict": req.MaxTokens,
+			"temperature": req.Temperature,
+		},
+	}
+	if req.JSON {
+		reqBody["format"] = "json"
+	}
+
+	var result struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}
+
+	if err := postJSON(ctx, p.client, "ollama", p.baseURL+"/api/chat", nil, reqBody, &result); err != nil {
+		return "", err
+	}
+
+	if result.Message.Content == "" {
+		return "", fmt.Errorf("no response from llm")
+	}
+
+	return result.Message.Content, nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "gpt-3.5-turbo"
+)
+
+// OpenAIProvider calls an OpenAI chat completions endpoint, or any server
+// exposing a compatible one, such as vLLM or LM Studio.
+type OpenAIProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAIProvider creates a provider for the chat completions API under
+// baseURL, e.g. "https://api.openai.com/v1". apiKey may be empty for servers
+// that don't require one.
+func NewOpenAIProvider(baseURL, apiKey, model string) *OpenAIProvider {
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+
+	return &OpenAIProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  newLLMHTTPClient(),
+	}
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	reqBody := map[string]interface{}{
+		"model": p.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": req.System},
+			{"role": "user", "content": req.Prompt},
+		},
+		"max_tokens":  req.MaxTokens,
+		"temperature": req.Temperature,
+	}
+	if req.JSON {
+		reqBody["response_format"] = map[string]string{"type": "json_object"}
+	}
+
+	header := http.Header{}
+	if p.apiKey != "" {
+		header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+
+	if err := postJSON(ctx, p.client, "openai", p.baseURL+"/chat/completions", header, reqBody, &result); err != nil {
+		return "", err
+	}
+
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("no response from llm")
+	}
+
+	return result.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries bounds how often a request hitting a secondary
+	// rate limit is retried before giving up.
+	maxRateLimitRetries = 3
+	initialBackoff      = time.Second
+	// maxBackoff caps a single wait, so a long Retry-After fails the request
+	// rather than holding it open.
+	maxBackoff = 30 * time.Second
+)
+
+// RateLimitError is returned when GitHub rejects a request for exceeding a
+// rate limit, or when the token's remaining budget is known to be spent.
+type RateLimitError struct {
+	Limit     int
+	Remaining int
+	// Reset is when the primary rate limit window resets.
+	Reset time.Time
+	// RetryAfter is the wait GitHub asked for on a secondary rate limit.
+	RetryAfter time.Duration
+	// Secondary reports an abuse-detection limit rather than the hourly quota.
+	Secondary bool
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Secondary {
+		return fmt.Sprintf("github secondary rate limit exceeded, retry after %s", e.RetryAfter)
+	}
+	return fmt.Sprintf("github rate limit of %d requests exceeded, resets at %s", e.Limit, e.Reset.Format(time.RFC3339))
+}
+
+// RetryAfterDuration is how long to wait before retrying, at least a second.
+// Secondary limits without a Retry-After header call for at least a minute;
+// the primary limit's reset doesn't apply to them.
+func (e *RateLimitError) RetryAfterDuration() time.Duration {
+	wait := e.RetryAfter
+	if e.Secondary && wait == 0 {
+		wait = time.Minute
+	}
+	if until := time.Until(e.Reset); !e.Secondary && until > wait {
+		wait = until
+	}
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return wait
+}
+
+// rateLimitStatus is the last known primary rate limit budget of a token.
+type rateLimitStatus struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// rateLimitTracker records the X-RateLimit-* headers of each token's latest
+// response, so that requests are refused locally once a budget is spent
+// instead of being sent to GitHub only to be rejected. Tokens are keyed by
+// their hash.
+type rateLimitTracker struct {
+	mu     sync.Mutex
+	tokens map[string]rateLimitStatus
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{tokens: make(map[string]rateLimitStatus)}
+}
+
+func (t *rateLimitTracker) update(token string, header http.Header) {
+	status, ok := parseRateLimitHeaders(header)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for key, existing := range t.tokens {
+		if existing.Reset.Before(now) {
+			delete(t.tokens, key)
+		}
+	}
+	t.tokens[tokenHash(token)] = status
+}
+
+func (t *rateLimitTracker) status(token string) (rateLimitStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status, ok := t.tokens[tokenHash(token)]
+	if !ok || status.Reset.Before(time.Now()) {
+		return rateLimitStatus{}, false
+	}
+	return status, true
+}
+
+// check returns a RateLimitError if the token's budget is known to be spent.
+func (t *rateLimitTracker) check(token string) error {
+	if status, ok := t.status(token); ok && status.Remaining == 0 {
+		return &RateLimitError{Limit: status.Limit, Remaining: 0, Reset: status.Reset}
+	}
+	return nil
+}
+
+func parseRateLimitHeaders(header http.Header) (rateLimitStatus, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return rateLimitStatus{}, false
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	return rateLimitStatus{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+	}, true
+}
+
+// send performs the request built by newRequest, retrying with exponential
+// backoff while GitHub reports a secondary rate limit. Exhausted primary and
+// secondary limits are returned as a *RateLimitError; any other response is
+// returned for the caller to handle.
+func (t *rateLimitTracker) send(ctx context.Context, httpClient *http.Client, token string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	if err := t.check(token); err != nil {
+		return nil, err
+	}
+
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		t.update(token, resp.Header)
+
+		rateErr := rateLimitError(resp)
+		if rateErr == nil {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if !rateErr.Secondary || attempt == maxRateLimitRetries {
+			return nil, rateErr
+		}
+
+		wait := backoff
+		if rateErr.RetryAfter > wait {
+			wait = rateErr.RetryAfter
+		}
+		if wait > maxBackoff {
+			return nil, rateErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// rateLimitError classifies a 403 or 429 response as a primary or secondary
+// rate limit, reading the body if the headers are ambiguous. It returns nil
+// for any other response, restoring the body it read.
+func rateLimitError(resp *http.Response) *RateLimitError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	status, hasStatus := parseRateLimitHeaders(resp.Header)
+	err := &RateLimitError{Limit: status.Limit, Remaining: status.Remaining, Reset: status.Reset}
+
+	if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil {
+		err.Secondary = true
+		err.RetryAfter = time.Duration(seconds) * time.Second
+		return err
+	}
+
+	if hasStatus && status.Remaining == 0 {
+		return err
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	if strings.Contains(strings.ToLower(string(body)), "secondary rate limit") || resp.StatusCode == http.StatusTooManyRequests {
+		err.Secondary = true
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+const (
+	// statsAttempts bounds how often a statistics request is retried while
+	// GitHub computes it in the background.
+	statsAttempts   = 3
+	statsRetryDelay = 2 * time.Second
+)
+
+type contributorStats struct {
+	Total  int `json:"total"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Weeks []struct {
+		Week      int64 `json:"w"`
+		Additions int   `json:"a"`
+		Deletions int   `json:"d"`
+		Commits   int   `json:"c"`
+	} `json:"weeks"`
+}
+
+// GetAuthorship returns the user's share of a repository's commit history,
+// computed from the contributor statistics of its default branch, with a zero
+// share if the user hasn't committed to it. It returns nil if the repository
+// has no statistics, and ErrStatsPending if they aren't ready after a few
+// attempts.
+func (c *GitHubClient) GetAuthorship(ctx context.Context, token, fullName, login string) (*model.Authorship, error) {
+	var stats []contributorStats
+
+	for attempt := 1; ; attempt++ {
+		_, err := c.do(ctx, "GET", c.baseURL+"/repos/"+fullName+"/stats/contributors", token, &stats)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		if !errors.Is(err, ErrStatsPending) || attempt == statsAttempts {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(statsRetryDelay):
+		}
+	}
+
+	return authorshipFromStats(stats, login), nil
+}
+
+func authorshipFromStats(stats []contributorStats, login string) *model.Authorship {
+	authorship := &model.Authorship{}
+	var total int
+
+	for _, contributor := range stats {
+		total += contributor.Total
+		if contributor.Author == nil || !strings.EqualFold(contributor.Author.Login, login) {
+			continue
+		}
+
+		authorship.Commits = contributor.Total
+		for _, week := range contributor.Weeks {
+			authorship.Additions += week.Additions
+			authorship.Deletions += week.Deletions
+			if week.Commits == 0 {
+				continue
+			}
+
+			start := time.Unix(week.Week, 0).UTC()
+			if authorship.ActiveFrom.IsZero() {
+				authorship.ActiveFrom = start
+			}
+			authorship.ActiveTo = start
+		}
+	}
+
+	// An empty history has no shares to speak of.
+	if total == 0 {
+		return nil
+	}
+
+	authorship.TotalCommits = total
+	authorship.Share = float64(authorship.Commits) / float64(total)
+	return authorship
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,7 +17,9 @@ type Config struct {
 	GitHub   GitHubConfig
 	Crypto   CryptoConfig
 	Redis    RedisConfig
-	OpenAI   OpenAIConfig
+	LLM      LLMConfig
+	Refresh  RefreshConfig
+	Jobs     JobsConfig
 }
 
 type ServerConfig struct {
@@ -35,6 +40,31 @@ type GitHubConfig struct {
 	ClientID     string
 	ClientSecret string
 	RedirectURL  string
+	MaxRepoPages int
+	// API selects how resume data is fetched: "rest" or "graphql".
+	API string
+	// WebURL is where profiles are linked to, BaseURL, UploadURL and
+	// GraphQLURL are the API endpoints, and AuthURL and TokenURL the OAuth
+	// endpoints. They default to github.com, or to the GitHub Enterprise
+	// Server instance at GITHUB_ENTERPRISE_URL.
+	WebURL     string
+	BaseURL    string
+	UploadURL  string
+	GraphQLURL string
+	AuthURL    string
+	TokenURL   string
+	// AuthMode is "oauth" to read GitHub with each user's OAuth token, or
+	// "app" to sign users in through a GitHub App and read with installation
+	// tokens. In app mode ClientID and ClientSecret are the app's.
+	AuthMode      string
+	AppID         int64
+	AppPrivateKey []byte
+	// WebhookSecret verifies webhook deliveries. Webhooks, and the
+	// snapshots they keep current, are disabled without it.
+	WebhookSecret string
+	// SnapshotMaxAge is how long a snapshot is used before it is fetched
+	// from GitHub in full again.
+	SnapshotMaxAge time.Duration
 }
 
 type CryptoConfig struct {
@@ -49,9 +79,39 @@ type RedisConfig struct {
 	Enabled  bool
 }
 
-type OpenAIConfig struct {
+// RefreshConfig controls the background refresh of stale resumes.
+type RefreshConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	// StaleAfter is how long after their last fetch a user's GitHub data is
+	// refreshed.
+	StaleAfter time.Duration
+	// Action is "flag" to mark changed default resumes as stale, or
+	// "regenerate" to generate new ones.
+	Action string
+}
+
+type JobsConfig struct {
+	// Workers is how many resume generation jobs run at once per replica.
+	Workers int
+}
+
+// LLMConfig selects the model backend used to write summaries and project
+// descriptions.
+type LLMConfig struct {
+	// Provider is "openai" (OpenAI or an OpenAI-compatible server),
+	// "anthropic", "ollama" or "fake" for offline use.
+	Provider string
+	// BaseURL and Model override the provider's defaults when set.
+	BaseURL string
 	APIKey  string
+	Model   string
 	Enabled bool
+	// FixtureDir, when set, replays responses recorded there instead of
+	// calling the provider. With FixtureRecord, responses missing from it are
+	// fetched from the provider and recorded.
+	FixtureDir    string
+	FixtureRecord bool
 }
 
 func Load() (*Config, error) {
@@ -63,6 +123,102 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
 	}
 
+	maxRepoPages, err := strconv.Atoi(getEnv("GITHUB_MAX_REPO_PAGES", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GITHUB_MAX_REPO_PAGES: %w", err)
+	}
+
+	githubAPI := getEnv("GITHUB_API", "rest")
+	if githubAPI != "rest" && githubAPI != "graphql" {
+		return nil, fmt.Errorf("invalid GITHUB_API: %q (want rest or graphql)", githubAPI)
+	}
+
+	authMode := getEnv("GITHUB_AUTH_MODE", "oauth")
+	var appID int64
+	var appPrivateKey []byte
+	switch authMode {
+	case "oauth":
+	case "app":
+		appID, err = strconv.ParseInt(os.Getenv("GITHUB_APP_ID"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_APP_ID: %w", err)
+		}
+		appPrivateKey, err = loadPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid GITHUB_AUTH_MODE: %q (want oauth or app)", authMode)
+	}
+
+	snapshotMaxAge, err := time.ParseDuration(getEnv("GITHUB_SNAPSHOT_MAX_AGE", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GITHUB_SNAPSHOT_MAX_AGE: %w", err)
+	}
+
+	refreshInterval, err := time.ParseDuration(getEnv("RESUME_REFRESH_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESUME_REFRESH_INTERVAL: %w", err)
+	}
+	if refreshInterval <= 0 {
+		return nil, fmt.Errorf("invalid RESUME_REFRESH_INTERVAL: must be positive")
+	}
+
+	staleAfterDays, err := strconv.Atoi(getEnv("RESUME_STALE_AFTER_DAYS", "7"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESUME_STALE_AFTER_DAYS: %w", err)
+	}
+
+	refreshAction := getEnv("RESUME_REFRESH_ACTION", "flag")
+	if refreshAction != "flag" && refreshAction != "regenerate" {
+		return nil, fmt.Errorf("invalid RESUME_REFRESH_ACTION: %q (want flag or regenerate)", refreshAction)
+	}
+
+	jobWorkers, err := strconv.Atoi(getEnv("JOB_WORKERS", "4"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_WORKERS: %w", err)
+	}
+	if jobWorkers < 1 {
+		return nil, fmt.Errorf("invalid JOB_WORKERS: must be at least 1")
+	}
+
+	llmProvider := getEnv("LLM_PROVIDER", "openai")
+	if llmProvider != "openai" && llmProvider != "anthropic" && llmProvider != "ollama" && llmProvider != "fake" {
+		return nil, fmt.Errorf("invalid LLM_PROVIDER: %q (want openai, anthropic, ollama or fake)", llmProvider)
+	}
+
+	// The OPENAI_ variables predate other providers and are still accepted.
+	llmEnabled := getEnv("LLM_ENABLED", getEnv("OPENAI_ENABLED", "false")) == "true"
+	llmAPIKey := getEnv("LLM_API_KEY", getEnv("OPENAI_API_KEY", ""))
+	llmBaseURL := getEnv("LLM_BASE_URL", "")
+	llmFixtureDir := getEnv("LLM_FIXTURE_DIR", "")
+	llmFixtureRecord := getEnv("LLM_FIXTURE_RECORD", "false") == "true"
+	if llmFixtureRecord && llmFixtureDir == "" {
+		return nil, fmt.Errorf("LLM_FIXTURE_DIR is required when LLM_FIXTURE_RECORD is true")
+	}
+	// Only the providers behind a hosted API need a key, and replaying
+	// fixtures calls none.
+	replayOnly := llmFixtureDir != "" && !llmFixtureRecord
+	if llmEnabled && llmAPIKey == "" && llmBaseURL == "" && llmProvider != "ollama" && llmProvider != "fake" && !replayOnly {
+		return nil, fmt.Errorf("LLM_API_KEY is required when LLM_ENABLED is true for provider %s", llmProvider)
+	}
+
+	webURL := "https://github.com"
+	apiURL := "https://api.github.com"
+	uploadURL := "https://uploads.github.com"
+	graphqlURL := "https://api.github.com/graphql"
+
+	// GitHub Enterprise Server serves the API under /api on its own host.
+	if enterpriseURL := strings.TrimRight(getEnv("GITHUB_ENTERPRISE_URL", ""), "/"); enterpriseURL != "" {
+		if _, err := url.ParseRequestURI(enterpriseURL); err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_ENTERPRISE_URL: %w", err)
+		}
+		webURL = enterpriseURL
+		apiURL = enterpriseURL + "/api/v3"
+		uploadURL = enterpriseURL + "/api/uploads"
+		graphqlURL = enterpriseURL + "/api/graphql"
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
 			Port: getEnv("PORT", "8080"),
@@ -77,9 +233,22 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		GitHub: GitHubConfig{
-			ClientID:     mustGetEnv("GITHUB_CLIENT_ID"),
-			ClientSecret: mustGetEnv("GITHUB_CLIENT_SECRET"),
-			RedirectURL:  getEnv("GITHUB_REDIRECT_URL", "http://localhost:8080/auth/callback"),
+			ClientID:       mustGetEnv("GITHUB_CLIENT_ID"),
+			ClientSecret:   mustGetEnv("GITHUB_CLIENT_SECRET"),
+			RedirectURL:    getEnv("GITHUB_REDIRECT_URL", "http://localhost:8080/auth/callback"),
+			MaxRepoPages:   maxRepoPages,
+			API:            githubAPI,
+			BaseURL:        strings.TrimRight(getEnv("GITHUB_BASE_URL", apiURL), "/"),
+			UploadURL:      strings.TrimRight(getEnv("GITHUB_UPLOAD_URL", uploadURL), "/"),
+			WebURL:         webURL,
+			GraphQLURL:     getEnv("GITHUB_GRAPHQL_URL", graphqlURL),
+			AuthURL:        getEnv("GITHUB_AUTH_URL", webURL+"/login/oauth/authorize"),
+			TokenURL:       getEnv("GITHUB_TOKEN_URL", webURL+"/login/oauth/access_token"),
+			AuthMode:       authMode,
+			AppID:          appID,
+			AppPrivateKey:  appPrivateKey,
+			WebhookSecret:  getEnv("GITHUB_WEBHOOK_SECRET", ""),
+			SnapshotMaxAge: snapshotMaxAge,
 		},
 		Crypto: CryptoConfig{
 			EncryptionKey: mustGetEnv("ENCRYPTION_KEY"),
@@ -91,9 +260,23 @@ func Load() (*Config, error) {
 			DB:       0,
 			Enabled:  getEnv("REDIS_ENABLED", "false") == "true",
 		},
-		OpenAI: OpenAIConfig{
-			APIKey:  getEnv("OPENAI_API_KEY", ""),
-			Enabled: getEnv("OPENAI_ENABLED", "false") == "true",
+		LLM: LLMConfig{
+			Provider:      llmProvider,
+			BaseURL:       llmBaseURL,
+			APIKey:        llmAPIKey,
+			Model:         getEnv("LLM_MODEL", ""),
+			Enabled:       llmEnabled,
+			FixtureDir:    llmFixtureDir,
+			FixtureRecord: llmFixtureRecord,
+		},
+		Refresh: RefreshConfig{
+			Enabled:    getEnv("RESUME_REFRESH_ENABLED", "false") == "true",
+			Interval:   refreshInterval,
+			StaleAfter: time.Duration(staleAfterDays) * 24 * time.Hour,
+			Action:     refreshAction,
+		},
+		Jobs: JobsConfig{
+			Workers: jobWorkers,
 		},
 	}
 
@@ -107,6 +290,25 @@ func (c *DatabaseConfig) DSN() string {
 	)
 }
 
+// loadPrivateKey reads the GitHub App's PEM private key from the file at
+// GITHUB_APP_PRIVATE_KEY_PATH, or from GITHUB_APP_PRIVATE_KEY with "\n"
+// escapes standing for newlines.
+func loadPrivateKey() ([]byte, error) {
+	if path := os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH"); path != "" {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GITHUB_APP_PRIVATE_KEY_PATH: %w", err)
+		}
+		return key, nil
+	}
+
+	key := os.Getenv("GITHUB_APP_PRIVATE_KEY")
+	if key == "" {
+		return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_PATH is required when GITHUB_AUTH_MODE is app")
+	}
+	return []byte(strings.ReplaceAll(key, `\n`, "\n")), nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
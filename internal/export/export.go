@@ -0,0 +1,77 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// templateData is what the LaTeX and HTML templates are executed against.
+type templateData struct {
+	Resume   *model.Resume
+	User     *templateUser
+	Projects []model.ResumeProject
+}
+
+// templateUser is the part of the user's profile exposed to templates. It
+// deliberately leaves out the stored token since themes can be uploaded.
+type templateUser struct {
+	Username  string
+	Name      string
+	Email     string
+	AvatarURL string
+	// ProfileURL links to the user's GitHub profile, and Profile is the
+	// same without the scheme, for display.
+	ProfileURL string
+	Profile    string
+}
+
+// newTemplateData prepares a resume for the templates. webURL is the GitHub
+// instance profiles are linked on, such as https://github.com.
+func newTemplateData(resume *model.Resume, user *model.User, webURL string) templateData {
+	data := templateData{
+		Resume:   resume,
+		Projects: SortedProjects(resume),
+	}
+
+	if user != nil {
+		data.User = &templateUser{
+			Username:  user.Username,
+			Name:      user.Name,
+			Email:     user.Email,
+			AvatarURL: user.AvatarURL,
+		}
+		if user.Username != "" {
+			data.User.ProfileURL = strings.TrimRight(webURL, "/") + "/" + user.Username
+			data.User.Profile = strings.TrimPrefix(strings.TrimPrefix(data.User.ProfileURL, "https://"), "http://")
+		}
+	}
+
+	return data
+}
+
+// SortedProjects returns a copy of the resume projects ordered by Position.
+func SortedProjects(resume *model.Resume) []model.ResumeProject {
+	projects := make([]model.ResumeProject, len(resume.Projects))
+	copy(projects, resume.Projects)
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].Position < projects[j].Position
+	})
+
+	return projects
+}
+
+// projectMeta summarizes a project's language and stars on a single line.
+func projectMeta(project model.ResumeProject) string {
+	var parts []string
+	if project.Language != "" {
+		parts = append(parts, project.Language)
+	}
+	if project.Stars > 0 {
+		parts = append(parts, fmt.Sprintf("%d stars", project.Stars))
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,333 @@
+package export
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template/parse"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// DefaultTheme is used when no theme is requested.
+const DefaultTheme = "classic"
+
+const (
+	maxThemeSize  = 64 << 10
+	maxRenderSize = 2 << 20
+	// maxRangeDepth bounds how deeply an uploaded theme may nest ranges,
+	// which multiply the work of rendering.
+	maxRangeDepth = 2
+	renderTimeout = 5 * time.Second
+)
+
+var ErrInvalidTheme = errors.New("invalid theme")
+
+var themeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+//go:embed themes/*.html
+var themeFiles embed.FS
+
+var builtinThemes = loadBuiltinThemes()
+
+// themeFuncs is the complete set of functions available to themes, including
+// uploaded ones. Everything here must be pure and side-effect free; a theme
+// referencing any other function fails to parse.
+var themeFuncs = template.FuncMap{
+	"join":        strings.Join,
+	"upper":       strings.ToUpper,
+	"lower":       strings.ToLower,
+	"meta":        projectMeta,
+	"displayName": displayName,
+	"add":         func(a, b int) int { return a + b },
+	"date":        func(t time.Time, layout string) string { return t.Format(layout) },
+}
+
+// themeBuiltins are the text/template builtins uploaded themes may use on
+// top of themeFuncs. The rest, such as call, index and printf, are rejected.
+var themeBuiltins = map[string]bool{
+	"and": true,
+	"or":  true,
+	"not": true,
+	"len": true,
+	"eq":  true,
+	"ne":  true,
+	"lt":  true,
+	"le":  true,
+	"gt":  true,
+	"ge":  true,
+}
+
+// rangeFields are the fields an uploaded theme may range over. Each holds
+// no more than a resume does; ranging over anything else, such as a number,
+// would let a theme run for as long as it likes.
+var rangeFields = map[string]bool{
+	"Projects":     true,
+	"Skills":       true,
+	"Highlights":   true,
+	"Topics":       true,
+	"SkillWeights": true,
+	"SkillSources": true,
+}
+
+// BuiltinThemes lists the names of the bundled themes.
+func BuiltinThemes() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinTheme returns the bundled theme with the given name.
+func BuiltinTheme(name string) (*template.Template, bool) {
+	tmpl, ok := builtinThemes[name]
+	return tmpl, ok
+}
+
+// IsValidThemeName reports whether name can be used for an uploaded theme.
+func IsValidThemeName(name string) bool {
+	_, builtin := builtinThemes[name]
+	return !builtin && themeNamePattern.MatchString(name)
+}
+
+// ParseTheme compiles an uploaded theme against the sandboxed function set
+// and checks that it renders a sample resume.
+func ParseTheme(name, source string) (*template.Template, error) {
+	if len(source) > maxThemeSize {
+		return nil, fmt.Errorf("%w: template exceeds %d bytes", ErrInvalidTheme, maxThemeSize)
+	}
+
+	tmpl, err := template.New(name).Funcs(themeFuncs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+
+	if err := checkThemeNode(tmpl.Tree.Root, 0); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+
+	if _, err := HTML(sampleResume(), sampleUser(), "https://github.com", tmpl); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+
+	return tmpl, nil
+}
+
+// HTML renders a resume into a standalone HTML document with the given theme.
+// Rendering that takes longer than renderTimeout fails, and stops at the
+// theme's next write.
+func HTML(resume *model.Resume, user *model.User, webURL string, theme *template.Template) ([]byte, error) {
+	type rendered struct {
+		html []byte
+		err  error
+	}
+
+	done := make(chan rendered, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- rendered{err: fmt.Errorf("rendering panicked: %v", r)}
+			}
+		}()
+
+		var buf bytes.Buffer
+		out := &limitedWriter{w: &buf, n: maxRenderSize, deadline: time.Now().Add(renderTimeout)}
+		err := theme.Execute(out, newTemplateData(resume, user, webURL))
+		done <- rendered{html: buf.Bytes(), err: err}
+	}()
+
+	timer := time.NewTimer(renderTimeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-done:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return result.html, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("rendering took longer than %s", renderTimeout)
+	}
+}
+
+// checkThemeNode rejects the constructs that could keep an uploaded theme
+// rendering indefinitely: ranges over anything but the resume's lists,
+// ranges nested deeper than maxRangeDepth and template calls, which can
+// recurse. Functions outside themeFuncs and themeBuiltins are rejected too.
+func checkThemeNode(node parse.Node, depth int) error {
+	switch n := node.(type) {
+	case *parse.ActionNode:
+		return checkThemePipe(n.Pipe)
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkThemeNode(child, depth); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return checkThemeBranch(&n.BranchNode, depth)
+	case *parse.WithNode:
+		return checkThemeBranch(&n.BranchNode, depth)
+	case *parse.RangeNode:
+		if err := checkThemePipe(n.Pipe); err != nil {
+			return err
+		}
+		if depth >= maxRangeDepth {
+			return fmt.Errorf("range may not be nested more than %d deep", maxRangeDepth)
+		}
+		if !isRangeField(n.Pipe) {
+			return fmt.Errorf("range may only iterate over .Projects, .Skills, .Highlights, .Topics, .SkillWeights or .SkillSources")
+		}
+		if err := checkThemeNode(n.List, depth+1); err != nil {
+			return err
+		}
+		return checkThemeNode(n.ElseList, depth)
+	case *parse.TemplateNode:
+		return fmt.Errorf("themes may not call templates")
+	}
+	return nil
+}
+
+func checkThemeBranch(n *parse.BranchNode, depth int) error {
+	if err := checkThemePipe(n.Pipe); err != nil {
+		return err
+	}
+	if err := checkThemeNode(n.List, depth); err != nil {
+		return err
+	}
+	return checkThemeNode(n.ElseList, depth)
+}
+
+func checkThemePipe(pipe *parse.PipeNode) error {
+	if pipe == nil {
+		return nil
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			if err := checkThemeArg(arg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkThemeArg(arg parse.Node) error {
+	switch a := arg.(type) {
+	case *parse.IdentifierNode:
+		if _, ok := themeFuncs[a.Ident]; !ok && !themeBuiltins[a.Ident] {
+			return fmt.Errorf("function %q is not available to themes", a.Ident)
+		}
+	case *parse.PipeNode:
+		return checkThemePipe(a)
+	case *parse.ChainNode:
+		return checkThemeArg(a.Node)
+	}
+	return nil
+}
+
+// isRangeField reports whether pipe is a lone field reference, like
+// .Projects or $.Resume.Skills, to one of rangeFields.
+func isRangeField(pipe *parse.PipeNode) bool {
+	if len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+
+	var ident []string
+	switch arg := pipe.Cmds[0].Args[0].(type) {
+	case *parse.FieldNode:
+		ident = arg.Ident
+	case *parse.VariableNode:
+		ident = arg.Ident[1:]
+	default:
+		return false
+	}
+
+	return len(ident) > 0 && rangeFields[ident[len(ident)-1]]
+}
+
+func loadBuiltinThemes() map[string]*template.Template {
+	entries, err := themeFiles.ReadDir("themes")
+	if err != nil {
+		panic(err)
+	}
+
+	themes := make(map[string]*template.Template, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".html")
+		themes[name] = template.Must(template.New(entry.Name()).Funcs(themeFuncs).ParseFS(themeFiles, "themes/"+entry.Name()))
+	}
+
+	return themes
+}
+
+func displayName(data templateData) string {
+	if data.User != nil {
+		if data.User.Name != "" {
+			return data.User.Name
+		}
+		if data.User.Username != "" {
+			return data.User.Username
+		}
+	}
+	return data.Resume.Title
+}
+
+// limitedWriter bounds the size of rendered output and, by failing writes
+// after deadline, the time an abandoned rendering keeps running.
+type limitedWriter struct {
+	w        io.Writer
+	n        int
+	deadline time.Time
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if time.Now().After(l.deadline) {
+		return 0, fmt.Errorf("rendering took longer than %s", renderTimeout)
+	}
+	if len(p) > l.n {
+		return 0, fmt.Errorf("rendered output exceeds %d bytes", maxRenderSize)
+	}
+	l.n -= len(p)
+	return l.w.Write(p)
+}
+
+func sampleUser() *model.User {
+	return &model.User{
+		Username: "octocat",
+		Name:     "Mona Octocat",
+		Email:    "octocat@example.com",
+	}
+}
+
+func sampleResume() *model.Resume {
+	return &model.Resume{
+		Title:      "GitHub Resume",
+		TargetRole: "Backend Engineer",
+		Summary:    "Software engineer with 12 public repositories.",
+		Skills:     []string{"Go", "PostgreSQL", "Docker"},
+		Projects: []model.ResumeProject{{
+			RepoName:    "resume-builder",
+			Description: "Generates resumes from GitHub profiles.",
+			URL:         "https://github.com/octocat/resume-builder",
+			Stars:       42,
+			Language:    "Go",
+			Topics:      []string{"golang", "api"},
+			Highlights:  []string{"Actively maintained"},
+		}},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
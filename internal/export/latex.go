@@ -0,0 +1,196 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+var ErrUnknownTemplate = errors.New("unknown template")
+
+// DefaultLaTeXTemplate is used when no template is requested.
+const DefaultLaTeXTemplate = "moderncv"
+
+// LaTeX templates use << >> as delimiters so they don't collide with the
+// braces that make up most of the document.
+var latexTemplates = map[string]*template.Template{
+	"moderncv": newLaTeXTemplate("moderncv", moderncvTemplate),
+	"article":  newLaTeXTemplate("article", articleTemplate),
+}
+
+// LaTeX renders a resume as a standalone .tex document using the named
+// template. All user-provided text is escaped.
+func LaTeX(resume *model.Resume, user *model.User, webURL, templateName string) ([]byte, error) {
+	if templateName == "" {
+		templateName = DefaultLaTeXTemplate
+	}
+
+	tmpl, ok := latexTemplates[templateName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, templateName)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, newTemplateData(resume, user, webURL)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func newLaTeXTemplate(name, text string) *template.Template {
+	return template.Must(template.New(name).Delims("<<", ">>").Funcs(template.FuncMap{
+		"tex":       latexEscape,
+		"url":       latexURL,
+		"meta":      projectMeta,
+		"join":      strings.Join,
+		"firstName": func(name string) string { first, _ := splitName(name); return first },
+		"lastName":  func(name string) string { _, last := splitName(name); return last },
+	}).Parse(text))
+}
+
+var latexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+	`<`, `\textless{}`,
+	`>`, `\textgreater{}`,
+)
+
+func latexEscape(s string) string {
+	return latexReplacer.Replace(s)
+}
+
+var latexURLReplacer = strings.NewReplacer(
+	`\`, `/`,
+	`{`, `%7B`,
+	`}`, `%7D`,
+	`%`, `\%`,
+	`#`, `\#`,
+)
+
+// latexURL prepares a URL for use inside \url{} when it is itself an
+// argument to another command, where % and # must still be escaped.
+func latexURL(s string) string {
+	return latexURLReplacer.Replace(s)
+}
+
+func splitName(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndex(name, " "); i > 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+const moderncvTemplate = `\documentclass[11pt,a4paper,sans]{moderncv}
+\moderncvstyle{classic}
+\moderncvcolor{blue}
+\usepackage[utf8]{inputenc}
+\usepackage[T1]{fontenc}
+\usepackage[scale=0.8]{geometry}
+
+\name{<< with .User >><< if .Name >><< firstName .Name | tex >><< else >><< tex .Username >><< end >><< end >>}{<< with .User >><< lastName .Name | tex >><< end >>}
+<<- if .Resume.TargetRole >>
+\title{<< tex .Resume.TargetRole >>}
+<<- end >>
+<<- with .User >>
+<<- if .Email >>
+\email{<< tex .Email >>}
+<<- end >>
+<<- if eq .Profile (printf "github.com/%s" .Username) >>
+\social[github]{<< tex .Username >>}
+<<- else if .Profile >>
+\homepage{<< tex .Profile >>}
+<<- end >>
+<<- end >>
+
+\begin{document}
+\makecvtitle
+<< if .Resume.Summary >>
+\section{Summary}
+\cvitem{}{<< tex .Resume.Summary >>}
+<< end >>
+<<- if .Resume.Skills >>
+\section{Skills}
+\cvitem{}{<< join .Resume.Skills ", " | tex >>}
+<< end >>
+<<- if .Projects >>
+\section{Projects}
+<<- range .Projects >>
+\cventry{<< meta . | tex >>}{<< tex .RepoName >>}{<< with .URL >>\url{<< url . >>}<< end >>}{}{}{<< tex .Description >>
+<<- if .Highlights >>
+\begin{itemize}
+<<- range .Highlights >>
+\item << tex . >>
+<<- end >>
+\end{itemize}
+<<- end >>}
+<<- end >>
+<< end >>
+\end{document}
+`
+
+const articleTemplate = `\documentclass[11pt]{article}
+\usepackage[utf8]{inputenc}
+\usepackage[T1]{fontenc}
+\usepackage[margin=0.75in]{geometry}
+\usepackage{enumitem}
+\usepackage[hidelinks]{hyperref}
+\setlength{\parindent}{0pt}
+
+\begin{document}
+
+\begin{center}
+{\LARGE\bfseries << with .User >><< if .Name >><< tex .Name >><< else >><< tex .Username >><< end >><< else >><< tex .Resume.Title >><< end >>}\\[4pt]
+<<- if .Resume.TargetRole >>
+<< tex .Resume.TargetRole >>\\
+<<- end >>
+<<- with .User >>
+<<- if .Email >>
+\href{mailto:<< url .Email >>}{<< tex .Email >>} \textbar{}
+<<- end >>
+\url{<< url .ProfileURL >>}
+<<- end >>
+\end{center}
+<< if .Resume.Summary >>
+\section*{Summary}
+<< tex .Resume.Summary >>
+<< end >>
+<<- if .Resume.Skills >>
+\section*{Skills}
+<< join .Resume.Skills ", " | tex >>
+<< end >>
+<<- if .Projects >>
+\section*{Projects}
+<<- range .Projects >>
+
+\textbf{<< tex .RepoName >>}<< with meta . >> \hfill \textit{<< tex . >>}<< end >>\par
+<<- if .Description >>
+<< tex .Description >>
+<<- end >>
+<<- if .Highlights >>
+\begin{itemize}[nosep]
+<<- range .Highlights >>
+\item << tex . >>
+<<- end >>
+\end{itemize}
+<<- end >>
+<<- if .URL >>
+\url{<< url .URL >>}
+<<- end >>
+<<- end >>
+<< end >>
+\end{document}
+`
@@ -0,0 +1,314 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// US Letter in PDF points.
+const (
+	pdfPageWidth    = 612.0
+	pdfPageHeight   = 792.0
+	pdfMargin       = 54.0
+	pdfFooterHeight = 24.0
+	pdfBulletIndent = 12.0
+)
+
+type pdfFont int
+
+const (
+	fontRegular pdfFont = iota
+	fontBold
+)
+
+// resourceName is the name the font is registered under in each page's
+// resource dictionary.
+func (f pdfFont) resourceName() string {
+	if f == fontBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// PDF renders a resume into a paginated PDF document. Only the standard
+// Helvetica fonts are used so no font files need to be embedded.
+func PDF(resume *model.Resume) ([]byte, error) {
+	w := newPDFWriter()
+
+	w.paragraph(fontBold, 20, resume.Title, 0)
+	if resume.TargetRole != "" {
+		w.paragraph(fontRegular, 12, resume.TargetRole, 0)
+	}
+
+	if resume.Summary != "" {
+		w.heading("Summary")
+		w.paragraph(fontRegular, 10, resume.Summary, 0)
+	}
+
+	if len(resume.Skills) > 0 {
+		w.heading("Skills")
+		w.paragraph(fontRegular, 10, strings.Join(resume.Skills, ", "), 0)
+	}
+
+	projects := SortedProjects(resume)
+	if len(projects) > 0 {
+		w.heading("Projects")
+		for _, project := range projects {
+			w.space(6)
+			w.paragraph(fontBold, 11, project.RepoName, 0)
+			if meta := projectMeta(project); meta != "" {
+				w.paragraph(fontRegular, 9, meta, 0)
+			}
+			if project.Description != "" {
+				w.paragraph(fontRegular, 10, project.Description, 0)
+			}
+			for _, highlight := range project.Highlights {
+				w.bullet(fontRegular, 10, highlight)
+			}
+			if project.URL != "" {
+				w.paragraph(fontRegular, 9, project.URL, 0)
+			}
+		}
+	}
+
+	return w.bytes(), nil
+}
+
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	w.newPage()
+	return w
+}
+
+func (w *pdfWriter) newPage() {
+	w.pages = append(w.pages, &bytes.Buffer{})
+	w.y = pdfPageHeight - pdfMargin
+}
+
+func (w *pdfWriter) current() *bytes.Buffer {
+	return w.pages[len(w.pages)-1]
+}
+
+// advance moves the cursor down by height, starting a new page first if the
+// line would run into the footer.
+func (w *pdfWriter) advance(height float64) {
+	if w.y-height < pdfMargin+pdfFooterHeight {
+		w.newPage()
+	}
+	w.y -= height
+}
+
+func (w *pdfWriter) space(height float64) {
+	if w.y-height < pdfMargin+pdfFooterHeight {
+		return
+	}
+	w.y -= height
+}
+
+func (w *pdfWriter) drawText(font pdfFont, size, x, y float64, text []byte) {
+	fmt.Fprintf(w.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font.resourceName(), size, x, y, pdfEscape(text))
+}
+
+func (w *pdfWriter) heading(text string) {
+	w.space(12)
+	w.advance(13 * 1.4)
+	w.drawText(fontBold, 13, pdfMargin, w.y, winAnsi(text))
+	fmt.Fprintf(w.current(), "0.5 w %.2f %.2f m %.2f %.2f l S\n",
+		pdfMargin, w.y-4, pdfPageWidth-pdfMargin, w.y-4)
+	w.space(6)
+}
+
+func (w *pdfWriter) paragraph(font pdfFont, size float64, text string, indent float64) {
+	width := pdfPageWidth - 2*pdfMargin - indent
+	for _, line := range wrapText(winAnsi(text), font, size, width) {
+		w.advance(size * 1.35)
+		w.drawText(font, size, pdfMargin+indent, w.y, line)
+	}
+}
+
+func (w *pdfWriter) bullet(font pdfFont, size float64, text string) {
+	width := pdfPageWidth - 2*pdfMargin - pdfBulletIndent
+	for i, line := range wrapText(winAnsi(text), font, size, width) {
+		w.advance(size * 1.35)
+		if i == 0 {
+			w.drawText(font, size, pdfMargin+2, w.y, []byte{0x95})
+		}
+		w.drawText(font, size, pdfMargin+pdfBulletIndent, w.y, line)
+	}
+}
+
+// bytes assembles the page content streams into a complete PDF file,
+// numbering the pages in the footer now that the total is known.
+func (w *pdfWriter) bytes() []byte {
+	for i, page := range w.pages {
+		footer := winAnsi(fmt.Sprintf("Page %d of %d", i+1, len(w.pages)))
+		x := pdfPageWidth - pdfMargin - textWidth(footer, fontRegular, 8)
+		fmt.Fprintf(page, "BT /F1 8.0 Tf %.2f %.2f Td (%s) Tj ET\n", x, pdfMargin, pdfEscape(footer))
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are fixed; each page then takes a page object followed by
+	// its content stream.
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range w.pages {
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i,
+		))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// wrapText breaks text into lines no wider than width, splitting words that
+// are too long to fit on a line of their own (typically URLs).
+func wrapText(text []byte, font pdfFont, size, width float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+
+	for _, word := range bytes.Fields(text) {
+		for textWidth(word, font, size) > width {
+			if len(line) > 0 {
+				lines = append(lines, line)
+				line = nil
+			}
+			n := 1
+			for n < len(word) && textWidth(word[:n+1], font, size) <= width {
+				n++
+			}
+			lines = append(lines, word[:n])
+			word = word[n:]
+		}
+
+		candidate := word
+		if len(line) > 0 {
+			candidate = append(append(append([]byte{}, line...), ' '), word...)
+		}
+		if textWidth(candidate, font, size) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func textWidth(text []byte, font pdfFont, size float64) float64 {
+	widths := helveticaWidths
+	if font == fontBold {
+		widths = helveticaBoldWidths
+	}
+
+	var total int
+	for _, c := range text {
+		if c >= 32 && c <= 126 {
+			total += widths[c-32]
+		} else {
+			total += 556
+		}
+	}
+
+	return float64(total) * size / 1000
+}
+
+func pdfEscape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// winAnsiSpecials maps the characters that WinAnsiEncoding places in the
+// 0x80-0x9F range; everything else above Latin-1 is replaced.
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '•': 0x95,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '–': 0x96, '—': 0x97,
+	'™': 0x99,
+}
+
+func winAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			out = append(out, ' ')
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if c, ok := winAnsiSpecials[r]; ok {
+				out = append(out, c)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// Glyph widths for ASCII 32-126 from the standard Helvetica AFM metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// Text renders a resume as single-column plain text with upper-case section
+// headers, the layout applicant tracking systems parse most reliably.
+func Text(resume *model.Resume, user *model.User, webURL string) []byte {
+	data := newTemplateData(resume, user, webURL)
+	var buf bytes.Buffer
+
+	buf.WriteString(displayName(data) + "\n")
+	if resume.TargetRole != "" {
+		buf.WriteString(resume.TargetRole + "\n")
+	}
+	if contact := contactLine(data.User); contact != "" {
+		buf.WriteString(contact + "\n")
+	}
+
+	if resume.Summary != "" {
+		buf.WriteString("\nSUMMARY\n")
+		buf.WriteString(resume.Summary + "\n")
+	}
+
+	if len(resume.Skills) > 0 {
+		buf.WriteString("\nSKILLS\n")
+		buf.WriteString(strings.Join(resume.Skills, ", ") + "\n")
+	}
+
+	if len(data.Projects) > 0 {
+		buf.WriteString("\nPROJECTS\n")
+		for i, project := range data.Projects {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(project.RepoName + "\n")
+			if meta := projectMeta(project); meta != "" {
+				buf.WriteString(meta + "\n")
+			}
+			if project.URL != "" {
+				buf.WriteString(project.URL + "\n")
+			}
+			if project.Description != "" {
+				buf.WriteString(project.Description + "\n")
+			}
+			for _, highlight := range project.Highlights {
+				buf.WriteString("- " + highlight + "\n")
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func contactLine(user *templateUser) string {
+	if user == nil {
+		return ""
+	}
+
+	var parts []string
+	if user.Email != "" {
+		parts = append(parts, user.Email)
+	}
+	if user.Profile != "" {
+		parts = append(parts, user.Profile)
+	}
+	return strings.Join(parts, " | ")
+}
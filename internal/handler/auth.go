@@ -76,6 +76,27 @@ func (h *AuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
 }
 
+// StreamToken issues a short-lived token for GET /resumes/generate/stream,
+// which browsers' EventSource can only authenticate through the URL.
+func (h *AuthHandler) StreamToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	token, err := h.jwtService.GenerateStreamToken(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"token":      token,
+		"expires_in": int(service.StreamTokenTTL.Seconds()),
+	})
+}
+
 func generateState() string {
 	b := make([]byte, 32)
 	rand.Read(b)
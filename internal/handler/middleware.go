@@ -3,10 +3,14 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/yourusername/resume-builder/internal/client"
 	"github.com/yourusername/resume-builder/internal/service"
 )
 
@@ -55,6 +59,31 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// AuthenticateStream authenticates like Authenticate, but also accepts a
+// stream token in the token query parameter, since browsers' EventSource
+// can't set headers.
+func (m *AuthMiddleware) AuthenticateStream(next http.Handler) http.Handler {
+	authenticate := m.Authenticate(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			authenticate.ServeHTTP(w, r)
+			return
+		}
+
+		userID, err := m.jwtService.ValidateStreamToken(token)
+		if err != nil {
+			m.logger.Warn("invalid stream token", "error", err, "path", r.URL.Path)
+			respondError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		m.logger.Info("authenticated request", "user_id", userID, "path", r.URL.Path)
+		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func GetUserID(ctx context.Context) (int64, bool) {
 	userID, ok := ctx.Value(userIDKey).(int64)
 	return userID, ok
@@ -71,3 +100,19 @@ func respondJSON(w http.ResponseWriter, code int, data interface{}) {
 	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(data)
 }
+
+func respondFile(w http.ResponseWriter, contentType, filename string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// respondRateLimited reports an exhausted GitHub rate limit as a 429 telling
+// the client when to retry.
+func respondRateLimited(w http.ResponseWriter, err *client.RateLimitError) {
+	seconds := int(math.Ceil(err.RetryAfterDuration().Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	respondError(w, http.StatusTooManyRequests, "github rate limit exceeded, retry later")
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeText = "text/plain"
+	contentTypePDF  = "application/pdf"
+	contentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// negotiateContentType picks the offer the Accept header prefers, honouring
+// q-values and wildcards. Each offer takes the q-value of the most specific
+// range matching it, so q=0 excludes the types it names. Ties go to the
+// earlier offer, and an empty header selects the first one. It returns ""
+// when nothing acceptable is offered.
+func negotiateContentType(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	type mediaRange struct {
+		mediaType string
+		q         float64
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			if s := matchMediaType(r.mediaType, offer); s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// matchMediaType reports how specifically pattern matches offer: 2 for an
+// exact match, 1 for type/*, 0 for */* and -1 for no match.
+func matchMediaType(pattern, offer string) int {
+	if pattern == offer {
+		return 2
+	}
+	if pattern == "*/*" {
+		return 0
+	}
+	if strings.HasSuffix(pattern, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(pattern, "*")) {
+		return 1
+	}
+	return -1
+}
@@ -1,11 +1,18 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/resume-builder/internal/client"
+	"github.com/yourusername/resume-builder/internal/export"
 	"github.com/yourusername/resume-builder/internal/model"
 	"github.com/yourusername/resume-builder/internal/service"
 )
@@ -13,12 +20,19 @@ import (
 type ResumeHandler struct {
 	resumeService *service.ResumeService
 	authService   *service.AuthService
+	themeService  *service.ThemeService
+	jobService    *service.JobService
+	// githubWebURL is the GitHub instance exported resumes link profiles on.
+	githubWebURL string
 }
 
-func NewResumeHandler(resumeService *service.ResumeService, authService *service.AuthService) *ResumeHandler {
+func NewResumeHandler(resumeService *service.ResumeService, authService *service.AuthService, themeService *service.ThemeService, jobService *service.JobService, githubWebURL string) *ResumeHandler {
 	return &ResumeHandler{
 		resumeService: resumeService,
 		authService:   authService,
+		themeService:  themeService,
+		jobService:    jobService,
+		githubWebURL:  githubWebURL,
 	}
 }
 
@@ -31,6 +45,7 @@ func (h *ResumeHandler) Generate(w http.ResponseWriter, r *http.Request) {
 
 	var req struct {
 		TargetRole string `json:"target_role"`
+		PinnedOnly bool   `json:"pinned_only"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -38,19 +53,97 @@ func (h *ResumeHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.authService.GetUserToken(r.Context(), userID)
-	if err != nil {
+	// Without a token the job could only fail, so that's reported up front.
+	if _, err := h.authService.GetUserToken(r.Context(), userID); err != nil {
+		if errors.Is(err, client.ErrAppNotInstalled) {
+			respondError(w, http.StatusForbidden, "github app is not installed on your account")
+			return
+		}
+		var rateErr *client.RateLimitError
+		if errors.As(err, &rateErr) {
+			respondRateLimited(w, rateErr)
+			return
+		}
 		respondError(w, http.StatusInternalServerError, "failed to get user token")
 		return
 	}
 
-	resume, err := h.resumeService.GenerateResume(r.Context(), userID, req.TargetRole, token)
+	job, err := h.jobService.Enqueue(r.Context(), userID, service.GenerateOptions{
+		TargetRole: req.TargetRole,
+		PinnedOnly: req.PinnedOnly,
+	})
+	if errors.Is(err, service.ErrGenerationInProgress) {
+		w.Header().Set("Location", fmt.Sprintf("/jobs/%d", job.ID))
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "failed to generate resume")
+		respondError(w, http.StatusInternalServerError, "failed to queue resume generation")
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, resume)
+	w.Header().Set("Location", fmt.Sprintf("/jobs/%d", job.ID))
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// generateStreamTimeout bounds a generation streamed by GenerateStream.
+const generateStreamTimeout = 5 * time.Minute
+
+// GenerateStream generates a resume while the client waits, streaming its
+// progress as Server-Sent Events: one event per GenerationEvent, named by its
+// type, then either a "resume" event with the resume or an "error" event with
+// a GenerationError.
+func (h *ResumeHandler) GenerateStream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	var pinnedOnly bool
+	if value := query.Get("pinned_only"); value != "" {
+		var err error
+		if pinnedOnly, err = strconv.ParseBool(value); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid pinned_only")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), generateStreamTimeout)
+	defer cancel()
+
+	stream, err := startSSE(w, time.Now().Add(generateStreamTimeout+10*time.Second))
+	if err != nil {
+		slog.Error("failed to start event stream", "error", err)
+		respondError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	stopKeepAlive := stream.keepAlive()
+	defer stopKeepAlive()
+
+	token, err := h.authService.GetUserToken(ctx, userID)
+	if err != nil {
+		slog.Warn("failed to get user token", "error", err, "user_id", userID)
+		stream.send("error", service.DescribeGenerationError(err))
+		return
+	}
+
+	resume, err := h.jobService.Generate(ctx, userID, token, service.GenerateOptions{
+		TargetRole: query.Get("target_role"),
+		PinnedOnly: pinnedOnly,
+		Progress: func(event service.GenerationEvent) {
+			stream.send(event.Type, event)
+		},
+	})
+	if err != nil {
+		slog.Warn("failed to generate resume", "error", err, "user_id", userID)
+		stream.send("error", service.DescribeGenerationError(err))
+		return
+	}
+
+	stream.send("resume", resume)
 }
 
 func (h *ResumeHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -66,13 +159,215 @@ func (h *ResumeHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	contentType := negotiateContentType(r.Header.Get("Accept"), []string{
+		contentTypeJSON, contentTypeText, contentTypeDOCX, contentTypePDF,
+	})
+	if contentType == "" {
+		respondError(w, http.StatusNotAcceptable, "unsupported accept header")
+		return
+	}
+
 	resume, err := h.resumeService.GetResume(r.Context(), resumeID, userID)
 	if err != nil {
 		respondError(w, http.StatusNotFound, err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusOK, resume)
+	w.Header().Set("Vary", "Accept")
+
+	switch contentType {
+	case contentTypeJSON:
+		respondJSON(w, http.StatusOK, resume)
+		return
+	case contentTypePDF:
+		respondPDF(w, resume)
+		return
+	}
+
+	user, err := h.authService.GetUser(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+
+	if contentType == contentTypeText {
+		respondFile(w, "text/plain; charset=utf-8", fmt.Sprintf("resume-%d.txt", resume.ID), export.Text(resume, user, h.githubWebURL))
+		return
+	}
+
+	docx, err := export.DOCX(resume, user, h.githubWebURL)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to render docx")
+		return
+	}
+	respondFile(w, contentTypeDOCX, fmt.Sprintf("resume-%d.docx", resume.ID), docx)
+}
+
+func (h *ResumeHandler) ExportPDF(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	resumeID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid resume id")
+		return
+	}
+
+	resume, err := h.resumeService.GetResume(r.Context(), resumeID, userID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondPDF(w, resume)
+}
+
+func respondPDF(w http.ResponseWriter, resume *model.Resume) {
+	pdf, err := export.PDF(resume)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to render pdf")
+		return
+	}
+
+	respondFile(w, contentTypePDF, fmt.Sprintf("resume-%d.pdf", resume.ID), pdf)
+}
+
+func (h *ResumeHandler) ExportLaTeX(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	resumeID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid resume id")
+		return
+	}
+
+	resume, err := h.resumeService.GetResume(r.Context(), resumeID, userID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	user, err := h.authService.GetUser(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+
+	tex, err := export.LaTeX(resume, user, h.githubWebURL, r.URL.Query().Get("template"))
+	if errors.Is(err, export.ErrUnknownTemplate) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to render latex")
+		return
+	}
+
+	respondFile(w, "application/x-tex; charset=utf-8", fmt.Sprintf("resume-%d.tex", resume.ID), tex)
+}
+
+func (h *ResumeHandler) Render(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	resumeID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid resume id")
+		return
+	}
+
+	resume, err := h.resumeService.GetResume(r.Context(), resumeID, userID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	user, err := h.authService.GetUser(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+
+	html, err := h.themeService.RenderResume(r.Context(), resume, user, r.URL.Query().Get("theme"))
+	if errors.Is(err, export.ErrUnknownTemplate) || errors.Is(err, export.ErrInvalidTheme) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to render resume")
+		return
+	}
+
+	// Themes may be user-uploaded, so the page is not allowed to run scripts
+	// or load anything beyond inline styles and images.
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src https: data:")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(html)
+}
+
+func (h *ResumeHandler) ExportJSONResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	resumeID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid resume id")
+		return
+	}
+
+	doc, err := h.resumeService.ExportJSONResume(r.Context(), resumeID, userID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to encode json resume")
+		return
+	}
+
+	respondFile(w, "application/json", fmt.Sprintf("resume-%d.json", resumeID), data)
+}
+
+func (h *ResumeHandler) ImportJSONResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var doc model.JSONResume
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resume, err := h.resumeService.ImportJSONResume(r.Context(), userID, &doc)
+	if errors.Is(err, service.ErrInvalidJSONResume) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to import resume")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, resume)
 }
 
 func (h *ResumeHandler) List(w http.ResponseWriter, r *http.Request) {
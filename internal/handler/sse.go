@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sseKeepAlive is how often a comment is sent on an idle event stream, so
+// proxies don't close it.
+const sseKeepAlive = 15 * time.Second
+
+// sseWriter writes Server-Sent Events. It is safe for concurrent use.
+type sseWriter struct {
+	mu sync.Mutex
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// startSSE sends the headers of an event stream, allowing writes to it
+// until deadline.
+func startSSE(w http.ResponseWriter, deadline time.Time) (*sseWriter, error) {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("failed to extend write deadline: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	s := &sseWriter{w: w, rc: rc}
+	return s, s.flush()
+}
+
+func (s *sseWriter) send(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// keepAlive sends a comment every sseKeepAlive until the returned function
+// is called. That function waits for the last comment to be written, so it
+// must be called before the handler returns.
+func (s *sseWriter) keepAlive() (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(sseKeepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				s.mu.Lock()
+				if _, err := fmt.Fprint(s.w, ": keep-alive\n\n"); err == nil {
+					s.flush()
+				}
+				s.mu.Unlock()
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func (s *sseWriter) flush() error {
+	return s.rc.Flush()
+}
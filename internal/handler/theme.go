@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/resume-builder/internal/export"
+	"github.com/yourusername/resume-builder/internal/service"
+)
+
+type ThemeHandler struct {
+	themeService *service.ThemeService
+}
+
+func NewThemeHandler(themeService *service.ThemeService) *ThemeHandler {
+	return &ThemeHandler{themeService: themeService}
+}
+
+func (h *ThemeHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	themes, err := h.themeService.ListThemes(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list themes")
+		return
+	}
+
+	custom := make([]string, len(themes))
+	for i, theme := range themes {
+		custom[i] = theme.Name
+	}
+
+	respondJSON(w, http.StatusOK, map[string][]string{
+		"builtin": export.BuiltinThemes(),
+		"custom":  custom,
+	})
+}
+
+func (h *ThemeHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req struct {
+		Name     string `json:"name"`
+		Template string `json:"template"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	theme, err := h.themeService.SaveTheme(r.Context(), userID, req.Name, req.Template)
+	if errors.Is(err, export.ErrInvalidTheme) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to save theme")
+		return
+	}
+
+	// The source was just sent by the client, so only the metadata is echoed.
+	respondJSON(w, http.StatusCreated, struct {
+		ID        int64
+		Name      string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}{theme.ID, theme.Name, theme.CreatedAt, theme.UpdatedAt})
+}
+
+func (h *ThemeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	err := h.themeService.DeleteTheme(r.Context(), userID, chi.URLParam(r, "name"))
+	if errors.Is(err, service.ErrThemeNotFound) {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete theme")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/yourusername/resume-builder/internal/service"
+)
+
+// maxWebhookPayload matches the largest payload GitHub delivers.
+const maxWebhookPayload = 25 << 20
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+	secret         []byte
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService, secret string) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		secret:         []byte(secret),
+	}
+}
+
+// GitHub receives webhook deliveries, rejecting any whose
+// X-Hub-Signature-256 doesn't match the shared secret.
+func (h *WebhookHandler) GitHub(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayload))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "failed to read payload")
+		return
+	}
+
+	if !h.validSignature(body, r.Header.Get("X-Hub-Signature-256")) {
+		respondError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	if err := h.webhookService.HandleEvent(r.Context(), event, body); err != nil {
+		if errors.Is(err, service.ErrInvalidWebhookPayload) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to handle github webhook", "error", err, "event", event, "delivery", r.Header.Get("X-GitHub-Delivery"))
+		respondError(w, http.StatusInternalServerError, "failed to handle webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) validSignature(body []byte, header string) bool {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,71 @@
+package model
+
+// JSONResume is the subset of the JSON Resume schema (https://jsonresume.org/schema)
+// that maps onto a Resume. Sections the builder has no use for are ignored on import.
+type JSONResume struct {
+	Schema    string                `json:"$schema,omitempty"`
+	Basics    JSONResumeBasics      `json:"basics"`
+	Work      []JSONResumeWork      `json:"work,omitempty"`
+	Education []JSONResumeEducation `json:"education,omitempty"`
+	Skills    []JSONResumeSkill     `json:"skills,omitempty"`
+	Projects  []JSONResumeProject   `json:"projects,omitempty"`
+	Meta      *JSONResumeMeta       `json:"meta,omitempty"`
+}
+
+type JSONResumeBasics struct {
+	Name     string              `json:"name,omitempty"`
+	Label    string              `json:"label,omitempty"`
+	Image    string              `json:"image,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Profiles []JSONResumeProfile `json:"profiles,omitempty"`
+}
+
+type JSONResumeProfile struct {
+	Network  string `json:"network,omitempty"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type JSONResumeWork struct {
+	Name      string `json:"name,omitempty"`
+	Position  string `json:"position,omitempty"`
+	URL       string `json:"url,omitempty"`
+	StartDate string `json:"startDate,omitempty"`
+	EndDate   string `json:"endDate,omitempty"`
+}
+
+type JSONResumeEducation struct {
+	Institution string `json:"institution,omitempty"`
+	URL         string `json:"url,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+}
+
+type JSONResumeSkill struct {
+	Name     string   `json:"name,omitempty"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// JSONResumeProject carries Language and Stars as additional properties,
+// which the schema permits on project entries, so they survive a round trip.
+type JSONResumeProject struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Stars       int      `json:"stars,omitempty"`
+}
+
+type JSONResumeMeta struct {
+	Canonical    string `json:"canonical,omitempty"`
+	Version      string `json:"version,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
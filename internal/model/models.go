@@ -22,20 +22,83 @@ type Resume struct {
 	Summary     string
 	Projects    []ResumeProject
 	Skills      []string
-	IsDefault   bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// SkillWeights maps languages to their share (0-1) of the code across
+	// the top repositories.
+	SkillWeights map[string]float64
+	// SkillSources records where each skill was found, as
+	// "<owner/repo>:<language|topic|manifest file>".
+	SkillSources map[string][]string
+	IsDefault    bool
+	// PinnedOnly records that the projects were chosen from pinned
+	// repositories only, so regenerating the resume does the same.
+	PinnedOnly bool
+	// GitHubChanges are the changes a background refresh found on GitHub,
+	// and StaleSince is set while the resume doesn't reflect them yet.
+	StaleSince    *time.Time
+	GitHubChanges *GitHubChanges
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// GenerationJob is a resume generation run in the background.
+type GenerationJob struct {
+	ID     int64
+	UserID int64
+	Status JobStatus
+	// Stage is the generation step the job is at, and Progress how much of
+	// the generation is done, in percent.
+	Stage      string
+	Progress   int
+	TargetRole string
+	PinnedOnly bool
+	// ResumeID is the generated resume once the job has succeeded.
+	ResumeID *int64
+	Error    string
+	// RetryAt is when a job that failed on GitHub's rate limit can be
+	// retried.
+	RetryAt *time.Time
+	// Attempts counts how often a worker has started the job.
+	Attempts   int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
 }
 
 type ResumeProject struct {
-	RepoName    string
-	Description string
-	URL         string
-	Stars       int
-	Language    string
-	Topics      []string
-	Highlights  []string
-	Position    int
+	RepoName       string
+	Description    string
+	URL            string
+	Stars          int
+	Language       string
+	Topics         []string
+	Highlights     []string
+	Position       int
+	IsContribution bool
+	Authorship     *Authorship
+}
+
+// Authorship is a user's share of a repository's commit history.
+type Authorship struct {
+	Commits      int
+	TotalCommits int
+	// Share is Commits / TotalCommits.
+	Share     float64
+	Additions int
+	Deletions int
+	// ActiveFrom and ActiveTo are the starts of the first and last weeks
+	// the user committed in.
+	ActiveFrom time.Time
+	ActiveTo   time.Time
 }
 
 type GitHubProfile struct {
@@ -47,6 +110,11 @@ type GitHubProfile struct {
 	Bio       string
 	Company   string
 	Location  string
+	// PinnedRepositories holds the full names of the repositories pinned
+	// to the profile, in display order.
+	PinnedRepositories []string
+	// TotalContributions is the contribution count for the past year.
+	TotalContributions int
 }
 
 type Repository struct {
@@ -63,10 +131,88 @@ type Repository struct {
 	UpdatedAt       time.Time
 	IsPrivate       bool
 	IsFork          bool
+	// Languages maps each language in the repository to its size in bytes.
+	Languages map[string]int
+}
+
+// StoredRepository is a repository as recorded by one fetch of a user's
+// repositories.
+type StoredRepository struct {
+	Repository
+	UserID    int64
+	FetchedAt time.Time
+}
+
+// GitHubChanges describes how a user's repositories changed between two
+// fetches.
+type GitHubChanges struct {
+	// Since is when the earlier fetch was made.
+	Since   time.Time
+	Added   []string
+	Removed []string
+	// Pushed lists repositories with commits pushed since the earlier fetch.
+	Pushed []string
+	// StarsGained is the change in stars across repositories in both fetches.
+	StarsGained int
+}
+
+func (c *GitHubChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Pushed) == 0 && c.StarsGained == 0
+}
+
+type ContributionKind string
+
+const (
+	// ContributionOrganization is an organization repository the user committed to.
+	ContributionOrganization ContributionKind = "organization"
+	// ContributionExternal is a repository the user had pull requests merged into.
+	ContributionExternal ContributionKind = "external"
+)
+
+// Contribution is a repository the user doesn't own but has worked on.
+type Contribution struct {
+	Repository
+	Kind               ContributionKind
+	CommitCount        int
+	MergedPullRequests int
+	LastContributedAt  time.Time
 }
 
 type RankedRepository struct {
 	Repository
-	Score      float64
-	Highlights []string
+	Score        float64
+	Highlights   []string
+	Contribution *Contribution
+	// Frameworks are detected from the repository's dependency manifests.
+	Frameworks []Framework
+	// Readme is the sanitized opening of the repository's README.
+	Readme     string
+	Authorship *Authorship
+}
+
+// GitHubSnapshot is a user's GitHub data persisted after a full fetch and
+// kept current by webhooks in between.
+type GitHubSnapshot struct {
+	UserID       int64
+	Profile      GitHubProfile
+	Repositories []Repository
+	// FetchedAt is when the snapshot was last fetched in full, UpdatedAt
+	// when it last changed.
+	FetchedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Framework is a framework or tool detected from a dependency manifest.
+type Framework struct {
+	Name     string
+	Manifest string
+}
+
+type Theme struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	Source    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// JobRepository stores resume generation jobs. It doubles as their queue:
+// workers on any replica claim queued jobs from it.
+type JobRepository struct {
+	db *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `id, user_id, status, stage, progress, target_role, pinned_only, resume_id, error, retry_at, attempts,
+		created_at, updated_at, started_at, finished_at`
+
+// Create queues the job unless its user already has one queued or running,
+// and reports whether it did.
+func (r *JobRepository) Create(ctx context.Context, job *model.GenerationJob) (bool, error) {
+	return r.insert(ctx, job, model.JobQueued)
+}
+
+// Start records a job its caller runs itself, outside the queue, as already
+// running. Like Create, it does nothing if the user already has a job queued
+// or running, and reports whether it recorded the job.
+func (r *JobRepository) Start(ctx context.Context, job *model.GenerationJob) (bool, error) {
+	return r.insert(ctx, job, model.JobRunning)
+}
+
+func (r *JobRepository) insert(ctx context.Context, job *model.GenerationJob, status model.JobStatus) (bool, error) {
+	query := `
+		INSERT INTO generation_jobs (user_id, status, target_role, pinned_only, attempts, created_at, updated_at, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6, $7)
+		ON CONFLICT (user_id) WHERE status IN ('queued', 'running') DO NOTHING
+		RETURNING id`
+
+	now := time.Now()
+	job.Status = status
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	if status == model.JobRunning {
+		job.Attempts = 1
+		job.StartedAt = &now
+	}
+
+	err := r.db.QueryRowContext(ctx, query, job.UserID, job.Status, job.TargetRole, job.PinnedOnly, job.Attempts, now, job.StartedAt).Scan(&job.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *JobRepository) GetByID(ctx context.Context, id int64) (*model.GenerationJob, error) {
+	query := `SELECT ` + jobColumns + ` FROM generation_jobs WHERE id = $1`
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// GetPending returns the user's queued or running job, or nil if there is
+// none.
+func (r *JobRepository) GetPending(ctx context.Context, userID int64) (*model.GenerationJob, error) {
+	query := `SELECT ` + jobColumns + ` FROM generation_jobs WHERE user_id = $1 AND status IN ($2, $3)`
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, userID, model.JobQueued, model.JobRunning))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// ClaimNext marks the oldest queued job as running and returns it, or nil if
+// there is none. Running jobs not updated since staleBefore, whose worker
+// presumably died, are claimed again.
+func (r *JobRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*model.GenerationJob, error) {
+	query := `
+		UPDATE generation_jobs
+		SET status = $1, attempts = attempts + 1, started_at = $2, updated_at = $2
+		WHERE id = (
+			SELECT id FROM generation_jobs
+			WHERE status = $3 OR (status = $1 AND updated_at < $4)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, model.JobRunning, time.Now(), model.JobQueued, staleBefore))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// UpdateProgress records the stage a running job has reached, which also
+// shows its worker is alive.
+func (r *JobRepository) UpdateProgress(ctx context.Context, id int64, stage string, progress int) error {
+	query := `UPDATE generation_jobs SET stage = $1, progress = $2, updated_at = $3 WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, query, stage, progress, time.Now(), id)
+	return err
+}
+
+func (r *JobRepository) Complete(ctx context.Context, id, resumeID int64) error {
+	query := `
+		UPDATE generation_jobs
+		SET status = $1, progress = 100, resume_id = $2, updated_at = $3, finished_at = $3
+		WHERE id = $4`
+
+	_, err := r.db.ExecContext(ctx, query, model.JobSucceeded, resumeID, time.Now(), id)
+	return err
+}
+
+// Fail records that the job failed with message. retryAt, if not nil, is
+// when it can be retried.
+func (r *JobRepository) Fail(ctx context.Context, id int64, message string, retryAt *time.Time) error {
+	query := `
+		UPDATE generation_jobs
+		SET status = $1, error = $2, retry_at = $3, updated_at = $4, finished_at = $4
+		WHERE id = $5`
+
+	_, err := r.db.ExecContext(ctx, query, model.JobFailed, message, retryAt, time.Now(), id)
+	return err
+}
+
+// Requeue returns a running job to the queue, for a worker that stops
+// before finishing it. The interrupted run doesn't count as an attempt.
+func (r *JobRepository) Requeue(ctx context.Context, id int64) error {
+	query := `
+		UPDATE generation_jobs
+		SET status = $1, stage = '', progress = 0, attempts = attempts - 1, updated_at = $2
+		WHERE id = $3 AND status = $4`
+
+	_, err := r.db.ExecContext(ctx, query, model.JobQueued, time.Now(), id, model.JobRunning)
+	return err
+}
+
+func scanJob(row rowScanner) (*model.GenerationJob, error) {
+	job := &model.GenerationJob{}
+	var targetRole, errorMessage sql.NullString
+	var resumeID sql.NullInt64
+	var retryAt, startedAt, finishedAt sql.NullTime
+
+	err := row.Scan(
+		&job.ID, &job.UserID, &job.Status, &job.Stage, &job.Progress, &targetRole, &job.PinnedOnly, &resumeID, &errorMessage, &retryAt, &job.Attempts,
+		&job.CreatedAt, &job.UpdatedAt, &startedAt, &finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.TargetRole = targetRole.String
+	job.Error = errorMessage.String
+	if resumeID.Valid {
+		job.ResumeID = &resumeID.Int64
+	}
+	if retryAt.Valid {
+		job.RetryAt = &retryAt.Time
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return job, nil
+}
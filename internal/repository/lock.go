@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Advisory lock keys, unique within the application.
+const (
+	RefreshLockKey int64 = 1
+)
+
+// AdvisoryLock is a session-level Postgres advisory lock, held on a dedicated
+// connection so that it is released if the process dies. Only one holder
+// across all replicas sharing the database can hold a given key. It is not
+// safe for concurrent use.
+type AdvisoryLock struct {
+	db   *sql.DB
+	key  int64
+	conn *sql.Conn
+}
+
+func NewAdvisoryLock(db *sql.DB, key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: db, key: key}
+}
+
+// TryAcquire reports whether the lock is held after trying to take it
+// without waiting. If the lock was held but its connection has been lost, it
+// is taken again.
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release gives up the lock if it is held.
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	if l.conn == nil {
+		return nil
+	}
+
+	_, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key)
+	l.conn.Close()
+	l.conn = nil
+	return err
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// RepoRepository records every fetch of a user's GitHub repositories, one
+// row per repository and fetch, so refreshes can tell what changed since the
+// previous fetch.
+type RepoRepository struct {
+	db *sql.DB
+}
+
+func NewRepoRepository(db *sql.DB) *RepoRepository {
+	return &RepoRepository{db: db}
+}
+
+const repoColumns = `user_id, name, full_name, description, url, stars, forks, language, topics, languages,
+		is_private, is_fork, last_commit_date, repo_created_at, repo_updated_at, fetched_at`
+
+// SaveFetch stores the repositories of one fetch, all stamped with
+// fetchedAt.
+func (r *RepoRepository) SaveFetch(ctx context.Context, userID int64, repos []model.Repository, fetchedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO repositories (`+repoColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (user_id, full_name, fetched_at) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, repo := range repos {
+		languagesJSON, err := marshalObject(repo.Languages)
+		if err != nil {
+			return err
+		}
+
+		_, err = stmt.ExecContext(
+			ctx,
+			userID, repo.Name, repo.FullName, repo.Description, repo.URL, repo.Stars, repo.Forks, repo.Language,
+			pq.Array(repo.Topics), languagesJSON, repo.IsPrivate, repo.IsFork,
+			nullTime(repo.LastCommitDate), nullTime(repo.CreatedAt), nullTime(repo.UpdatedAt), fetchedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLatest returns the repositories of the user's most recent fetch, or nil
+// if they were never fetched.
+func (r *RepoRepository) GetLatest(ctx context.Context, userID int64) ([]model.StoredRepository, error) {
+	query := `
+		SELECT ` + repoColumns + `
+		FROM repositories
+		WHERE user_id = $1 AND fetched_at = (SELECT MAX(fetched_at) FROM repositories WHERE user_id = $1)
+		ORDER BY last_commit_date DESC NULLS LAST`
+
+	return r.query(ctx, query, userID)
+}
+
+// PruneFetches deletes all but the user's keep most recent fetches.
+func (r *RepoRepository) PruneFetches(ctx context.Context, userID int64, keep int) error {
+	query := `
+		DELETE FROM repositories
+		WHERE user_id = $1 AND fetched_at < (
+			SELECT MIN(fetched_at) FROM (
+				SELECT DISTINCT fetched_at FROM repositories WHERE user_id = $1 ORDER BY fetched_at DESC LIMIT $2
+			) recent
+		)`
+
+	_, err := r.db.ExecContext(ctx, query, userID, keep)
+	return err
+}
+
+func (r *RepoRepository) query(ctx context.Context, query string, args ...interface{}) ([]model.StoredRepository, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var repos []model.StoredRepository
+	for rows.Next() {
+		var repo model.StoredRepository
+		var description, language sql.NullString
+		var languagesJSON []byte
+		var lastCommitDate, createdAt, updatedAt sql.NullTime
+
+		err := rows.Scan(
+			&repo.UserID, &repo.Name, &repo.FullName, &description, &repo.URL, &repo.Stars, &repo.Forks, &language,
+			pq.Array(&repo.Topics), &languagesJSON, &repo.IsPrivate, &repo.IsFork,
+			&lastCommitDate, &createdAt, &updatedAt, &repo.FetchedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(languagesJSON, &repo.Languages); err != nil {
+			return nil, err
+		}
+		repo.Description = description.String
+		repo.Language = language.String
+		repo.LastCommitDate = lastCommitDate.Time
+		repo.CreatedAt = createdAt.Time
+		repo.UpdatedAt = updatedAt.Time
+
+		repos = append(repos, repo)
+	}
+
+	return repos, rows.Err()
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// ListStaleUsers returns up to limit users with a default resume whose
+// repositories were last fetched, and last attempted to be refreshed, before
+// cutoff or never, least recently handled first.
+func (r *RepoRepository) ListStaleUsers(ctx context.Context, cutoff time.Time, limit int) ([]int64, error) {
+	query := `
+		SELECT u.id
+		FROM users u
+		LEFT JOIN (
+			SELECT user_id, MAX(fetched_at) AS fetched_at FROM repositories GROUP BY user_id
+		) f ON f.user_id = u.id
+		WHERE EXISTS (SELECT 1 FROM resumes WHERE user_id = u.id AND is_default)
+			AND (GREATEST(f.fetched_at, u.refresh_attempted_at) IS NULL OR GREATEST(f.fetched_at, u.refresh_attempted_at) < $1)
+		ORDER BY GREATEST(f.fetched_at, u.refresh_attempted_at) NULLS FIRST
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// RecordRefreshAttempt notes that the user's repositories were about to be
+// refreshed at, whether or not the refresh succeeds.
+func (r *RepoRepository) RecordRefreshAttempt(ctx context.Context, userID int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET refresh_attempted_at = $1 WHERE id = $2`, at, userID)
+	return err
+}
@@ -24,49 +24,62 @@ func (r *ResumeRepository) Create(ctx context.Context, resume *model.Resume) err
 		return err
 	}
 
+	skillWeightsJSON, err := marshalObject(resume.SkillWeights)
+	if err != nil {
+		return err
+	}
+
+	skillSourcesJSON, err := marshalObject(resume.SkillSources)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO resumes (user_id, title, target_role, summary, projects, skills, is_default, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO resumes (user_id, title, target_role, summary, projects, skills, skill_weights, skill_sources, is_default, pinned_only, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id`
 
 	now := time.Now()
 	return r.db.QueryRowContext(
 		ctx, query,
 		resume.UserID, resume.Title, resume.TargetRole, resume.Summary,
-		projectsJSON, pq.Array(resume.Skills), resume.IsDefault, now, now,
+		projectsJSON, pq.Array(resume.Skills), skillWeightsJSON, skillSourcesJSON, resume.IsDefault, resume.PinnedOnly, now, now,
 	).Scan(&resume.ID)
 }
 
 func (r *ResumeRepository) GetByID(ctx context.Context, id int64) (*model.Resume, error) {
 	query := `
-		SELECT id, user_id, title, target_role, summary, projects, skills, is_default, created_at, updated_at
+		SELECT id, user_id, title, target_role, summary, projects, skills, skill_weights, skill_sources, is_default, pinned_only, stale_since, github_changes, created_at, updated_at
 		FROM resumes
 		WHERE id = $1`
 
-	resume := &model.Resume{}
-	var projectsJSON []byte
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&resume.ID, &resume.UserID, &resume.Title, &resume.TargetRole, &resume.Summary,
-		&projectsJSON, pq.Array(&resume.Skills), &resume.IsDefault, &resume.CreatedAt, &resume.UpdatedAt,
-	)
+	resume, err := scanResume(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	if err != nil {
-		return nil, err
-	}
+	return resume, err
+}
 
-	if err := json.Unmarshal(projectsJSON, &resume.Projects); err != nil {
-		return nil, err
-	}
+// GetDefault returns the user's most recent default resume, or nil if they
+// have none.
+func (r *ResumeRepository) GetDefault(ctx context.Context, userID int64) (*model.Resume, error) {
+	query := `
+		SELECT id, user_id, title, target_role, summary, projects, skills, skill_weights, skill_sources, is_default, pinned_only, stale_since, github_changes, created_at, updated_at
+		FROM resumes
+		WHERE user_id = $1 AND is_default
+		ORDER BY created_at DESC
+		LIMIT 1`
 
-	return resume, nil
+	resume, err := scanResume(r.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return resume, err
 }
 
 func (r *ResumeRepository) ListByUserID(ctx context.Context, userID int64) ([]model.Resume, error) {
 	query := `
-		SELECT id, user_id, title, target_role, summary, projects, skills, is_default, created_at, updated_at
+		SELECT id, user_id, title, target_role, summary, projects, skills, skill_weights, skill_sources, is_default, pinned_only, stale_since, github_changes, created_at, updated_at
 		FROM resumes
 		WHERE user_id = $1
 		ORDER BY created_at DESC`
@@ -79,22 +92,11 @@ func (r *ResumeRepository) ListByUserID(ctx context.Context, userID int64) ([]mo
 
 	var resumes []model.Resume
 	for rows.Next() {
-		var resume model.Resume
-		var projectsJSON []byte
-
-		err := rows.Scan(
-			&resume.ID, &resume.UserID, &resume.Title, &resume.TargetRole, &resume.Summary,
-			&projectsJSON, pq.Array(&resume.Skills), &resume.IsDefault, &resume.CreatedAt, &resume.UpdatedAt,
-		)
+		resume, err := scanResume(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		if err := json.Unmarshal(projectsJSON, &resume.Projects); err != nil {
-			return nil, err
-		}
-
-		resumes = append(resumes, resume)
+		resumes = append(resumes, *resume)
 	}
 
 	return resumes, rows.Err()
@@ -106,21 +108,100 @@ func (r *ResumeRepository) Update(ctx context.Context, resume *model.Resume) err
 		return err
 	}
 
+	skillWeightsJSON, err := marshalObject(resume.SkillWeights)
+	if err != nil {
+		return err
+	}
+
+	skillSourcesJSON, err := marshalObject(resume.SkillSources)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE resumes
-		SET title = $1, target_role = $2, summary = $3, projects = $4, skills = $5, is_default = $6, updated_at = $7
-		WHERE id = $8`
+		SET title = $1, target_role = $2, summary = $3, projects = $4, skills = $5, skill_weights = $6, skill_sources = $7, is_default = $8, updated_at = $9
+		WHERE id = $10`
 
 	_, err = r.db.ExecContext(
 		ctx, query,
 		resume.Title, resume.TargetRole, resume.Summary,
-		projectsJSON, pq.Array(resume.Skills), resume.IsDefault, time.Now(), resume.ID,
+		projectsJSON, pq.Array(resume.Skills), skillWeightsJSON, skillSourcesJSON, resume.IsDefault, time.Now(), resume.ID,
 	)
 	return err
 }
 
+// SetGitHubChanges records the GitHub changes found by a background refresh
+// on the resume. stale flags the resume as not reflecting them yet.
+func (r *ResumeRepository) SetGitHubChanges(ctx context.Context, id int64, changes *model.GitHubChanges, stale bool) error {
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE resumes
+		SET stale_since = CASE WHEN $1 THEN COALESCE(stale_since, $2) END, github_changes = $3
+		WHERE id = $4`
+
+	_, err = r.db.ExecContext(ctx, query, stale, time.Now(), changesJSON, id)
+	return err
+}
+
 func (r *ResumeRepository) Delete(ctx context.Context, id int64) error {
 	query := `DELETE FROM resumes WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanResume(row rowScanner) (*model.Resume, error) {
+	resume := &model.Resume{}
+	var projectsJSON, skillWeightsJSON, skillSourcesJSON, changesJSON []byte
+	var staleSince sql.NullTime
+
+	err := row.Scan(
+		&resume.ID, &resume.UserID, &resume.Title, &resume.TargetRole, &resume.Summary,
+		&projectsJSON, pq.Array(&resume.Skills), &skillWeightsJSON, &skillSourcesJSON, &resume.IsDefault, &resume.PinnedOnly,
+		&staleSince, &changesJSON, &resume.CreatedAt, &resume.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(projectsJSON, &resume.Projects); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(skillWeightsJSON, &resume.SkillWeights); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(skillSourcesJSON, &resume.SkillSources); err != nil {
+		return nil, err
+	}
+
+	if staleSince.Valid {
+		resume.StaleSince = &staleSince.Time
+	}
+	if changesJSON != nil {
+		if err := json.Unmarshal(changesJSON, &resume.GitHubChanges); err != nil {
+			return nil, err
+		}
+	}
+
+	return resume, nil
+}
+
+// marshalObject encodes a map column, storing an empty object rather than
+// null for nil maps.
+func marshalObject(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err == nil && string(data) == "null" {
+		return []byte("{}"), nil
+	}
+	return data, err
+}
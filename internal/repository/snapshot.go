@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// SnapshotRepository stores each user's GitHub snapshot, looked up by their
+// GitHub login since that's all webhooks identify them by.
+type SnapshotRepository struct {
+	db *sql.DB
+}
+
+func NewSnapshotRepository(db *sql.DB) *SnapshotRepository {
+	return &SnapshotRepository{db: db}
+}
+
+// Save replaces the snapshot of the user with the given login after a full
+// fetch. It does nothing if no such user has signed up.
+func (r *SnapshotRepository) Save(ctx context.Context, login string, profile *model.GitHubProfile, repos []model.Repository) error {
+	profileJSON, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+
+	reposJSON, err := json.Marshal(repos)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO github_snapshots (user_id, profile, repositories, fetched_at, updated_at)
+		SELECT id, $2, $3, $4, $4 FROM users WHERE LOWER(username) = LOWER($1)
+		ON CONFLICT (user_id) DO UPDATE SET
+			profile = EXCLUDED.profile,
+			repositories = EXCLUDED.repositories,
+			fetched_at = EXCLUDED.fetched_at,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.ExecContext(ctx, query, login, profileJSON, reposJSON, time.Now())
+	return err
+}
+
+func (r *SnapshotRepository) GetByLogin(ctx context.Context, login string) (*model.GitHubSnapshot, error) {
+	query := `
+		SELECT s.user_id, s.profile, s.repositories, s.fetched_at, s.updated_at
+		FROM github_snapshots s
+		JOIN users u ON u.id = s.user_id
+		WHERE LOWER(u.username) = LOWER($1)`
+
+	snapshot, err := scanSnapshot(r.db.QueryRowContext(ctx, query, login))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return snapshot, err
+}
+
+// Update applies fn to the snapshots of the user with the given login and of
+// every user whose snapshot holds the repository fullName, such as members of
+// the organization owning it. The snapshots are updated under row locks, so
+// concurrent webhook deliveries don't overwrite each other. It returns how
+// many snapshots were updated.
+func (r *SnapshotRepository) Update(ctx context.Context, login, fullName string, fn func(*model.GitHubSnapshot)) (int, error) {
+	contains, err := json.Marshal([]struct{ FullName string }{{FullName: fullName}})
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Locking in user order keeps deliveries touching the same snapshots
+	// from deadlocking.
+	query := `
+		SELECT s.user_id, s.profile, s.repositories, s.fetched_at, s.updated_at
+		FROM github_snapshots s
+		JOIN users u ON u.id = s.user_id
+		WHERE LOWER(u.username) = LOWER($1) OR s.repositories @> $2
+		ORDER BY s.user_id
+		FOR UPDATE OF s`
+
+	rows, err := tx.QueryContext(ctx, query, login, contains)
+	if err != nil {
+		return 0, err
+	}
+
+	var snapshots []*model.GitHubSnapshot
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, snapshot := range snapshots {
+		fn(snapshot)
+
+		reposJSON, err := json.Marshal(snapshot.Repositories)
+		if err != nil {
+			return 0, err
+		}
+
+		snapshot.UpdatedAt = now
+		_, err = tx.ExecContext(ctx,
+			`UPDATE github_snapshots SET repositories = $1, updated_at = $2 WHERE user_id = $3`,
+			reposJSON, snapshot.UpdatedAt, snapshot.UserID,
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(snapshots), tx.Commit()
+}
+
+func scanSnapshot(row rowScanner) (*model.GitHubSnapshot, error) {
+	snapshot := &model.GitHubSnapshot{}
+	var profileJSON, reposJSON []byte
+
+	err := row.Scan(&snapshot.UserID, &profileJSON, &reposJSON, &snapshot.FetchedAt, &snapshot.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(profileJSON, &snapshot.Profile); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(reposJSON, &snapshot.Repositories); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
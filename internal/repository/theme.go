@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+type ThemeRepository struct {
+	db *sql.DB
+}
+
+func NewThemeRepository(db *sql.DB) *ThemeRepository {
+	return &ThemeRepository{db: db}
+}
+
+// Upsert stores a theme, replacing the source of an existing theme with the
+// same name for that user.
+func (r *ThemeRepository) Upsert(ctx context.Context, theme *model.Theme) error {
+	query := `
+		INSERT INTO themes (user_id, name, source, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, name) DO UPDATE SET source = EXCLUDED.source, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	return r.db.QueryRowContext(
+		ctx, query,
+		theme.UserID, theme.Name, theme.Source, now, now,
+	).Scan(&theme.ID, &theme.CreatedAt, &theme.UpdatedAt)
+}
+
+func (r *ThemeRepository) GetByName(ctx context.Context, userID int64, name string) (*model.Theme, error) {
+	query := `
+		SELECT id, user_id, name, source, created_at, updated_at
+		FROM themes
+		WHERE user_id = $1 AND name = $2`
+
+	theme := &model.Theme{}
+	err := r.db.QueryRowContext(ctx, query, userID, name).Scan(
+		&theme.ID, &theme.UserID, &theme.Name, &theme.Source, &theme.CreatedAt, &theme.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+func (r *ThemeRepository) ListByUserID(ctx context.Context, userID int64) ([]model.Theme, error) {
+	query := `
+		SELECT id, user_id, name, source, created_at, updated_at
+		FROM themes
+		WHERE user_id = $1
+		ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var themes []model.Theme
+	for rows.Next() {
+		var theme model.Theme
+		err := rows.Scan(
+			&theme.ID, &theme.UserID, &theme.Name, &theme.Source, &theme.CreatedAt, &theme.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		themes = append(themes, theme)
+	}
+
+	return themes, rows.Err()
+}
+
+// Delete removes the user's theme and reports whether there was one.
+func (r *ThemeRepository) Delete(ctx context.Context, userID int64, name string) (bool, error) {
+	query := `DELETE FROM themes WHERE user_id = $1 AND name = $2`
+	result, err := r.db.ExecContext(ctx, query, userID, name)
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+	return deleted > 0, err
+}
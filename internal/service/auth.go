@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/github"
 
 	"github.com/yourusername/resume-builder/internal/client"
 	"github.com/yourusername/resume-builder/internal/crypto"
@@ -18,24 +17,38 @@ type AuthService struct {
 	githubClient  *client.GitHubClient
 	encryptor     *crypto.Encryptor
 	oauthConfig   *oauth2.Config
+	// appClient is set in GitHub App mode, where users sign in through the
+	// app and GitHub is read with installation tokens instead of their own.
+	appClient *client.GitHubAppClient
 }
 
+// NewAuthService creates the auth service. With a nil appClient users
+// authorize an OAuth App for the repo scope; otherwise the client ID and
+// secret are the GitHub App's, whose fine-grained permissions replace scopes.
 func NewAuthService(
 	userRepo *repository.UserRepository,
 	githubClient *client.GitHubClient,
+	appClient *client.GitHubAppClient,
 	encryptor *crypto.Encryptor,
 	clientID, clientSecret, redirectURL string,
+	endpoint oauth2.Endpoint,
 ) *AuthService {
+	scopes := []string{"read:user", "user:email", "repo"}
+	if appClient != nil {
+		scopes = nil
+	}
+
 	return &AuthService{
 		userRepo:     userRepo,
 		githubClient: githubClient,
 		encryptor:    encryptor,
+		appClient:    appClient,
 		oauthConfig: &oauth2.Config{
 			ClientID:     clientID,
 			ClientSecret: clientSecret,
 			RedirectURL:  redirectURL,
-			Scopes:       []string{"read:user", "user:email", "repo"},
-			Endpoint:     github.Endpoint,
+			Scopes:       scopes,
+			Endpoint:     endpoint,
 		},
 	}
 }
@@ -91,6 +104,21 @@ func (s *AuthService) GetDecryptedToken(encryptedToken string) (string, error) {
 	return s.encryptor.Decrypt(encryptedToken)
 }
 
+func (s *AuthService) GetUser(ctx context.Context, userID int64) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, nil
+}
+
+// GetUserToken returns the token to read the user's GitHub data with: their
+// OAuth token, or in GitHub App mode an installation token for the app's
+// installation on their account, which returns client.ErrAppNotInstalled
+// if there is none.
 func (s *AuthService) GetUserToken(ctx context.Context, userID int64) (string, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -99,5 +127,8 @@ func (s *AuthService) GetUserToken(ctx context.Context, userID int64) (string, e
 	if user == nil {
 		return "", fmt.Errorf("user not found")
 	}
+	if s.appClient != nil {
+		return s.appClient.InstallationToken(ctx, user.Username)
+	}
 	return s.encryptor.Decrypt(user.EncryptedToken)
 }
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/client"
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+// Stages of resume generation, in order.
+const (
+	StageFetchingProfile   = "fetching_profile"
+	StageRankingRepos      = "ranking_repositories"
+	StageExtractingSkills  = "extracting_skills"
+	StageFetchingReadmes   = "fetching_readmes"
+	StageEnhancingProjects = "enhancing_projects"
+	StageWritingSummary    = "writing_summary"
+	StageSaving            = "saving"
+)
+
+var generationStages = []string{
+	StageFetchingProfile,
+	StageRankingRepos,
+	StageExtractingSkills,
+	StageFetchingReadmes,
+	StageEnhancingProjects,
+	StageWritingSummary,
+	StageSaving,
+}
+
+// StageProgress is roughly how much of a generation is done, in percent,
+// when it reaches stage.
+func StageProgress(stage string) int {
+	for i, s := range generationStages {
+		if s == stage {
+			return i * 100 / len(generationStages)
+		}
+	}
+	return 0
+}
+
+// Types of GenerationEvent.
+const (
+	// EventStage starts a stage.
+	EventStage = "stage"
+	// EventProfileFetched carries Login and RepoCount.
+	EventProfileFetched = "profile_fetched"
+	// EventRankingDone carries RepoCount, the number of candidates ranked,
+	// and TopRepositories.
+	EventRankingDone = "ranking_done"
+	// EventProjectEnhanced carries Project and ProjectCount.
+	EventProjectEnhanced = "project_enhanced"
+	// EventSummaryWritten carries Summary.
+	EventSummaryWritten = "summary_written"
+)
+
+// GenerationEvent reports a step of a resume generation. Besides Type,
+// Stage and Progress, it carries the fields its type documents.
+type GenerationEvent struct {
+	Type     string
+	Stage    string
+	Progress int
+
+	Login           string               `json:",omitempty"`
+	RepoCount       int                  `json:",omitempty"`
+	TopRepositories []string             `json:",omitempty"`
+	Project         *model.ResumeProject `json:",omitempty"`
+	ProjectCount    int                  `json:",omitempty"`
+	Summary         string               `json:",omitempty"`
+}
+
+// Codes of GenerationError.
+const (
+	ErrorCodeRateLimited     = "rate_limited"
+	ErrorCodeAppNotInstalled = "app_not_installed"
+	ErrorCodeTimeout         = "timeout"
+	ErrorCodeInProgress      = "in_progress"
+	ErrorCodeInternal        = "internal"
+)
+
+// GenerationError describes why a generation failed in terms fit to show
+// the user.
+type GenerationError struct {
+	Code    string
+	Message string
+	// RetryAt is when a rate limited generation can be retried.
+	RetryAt *time.Time `json:",omitempty"`
+}
+
+func DescribeGenerationError(err error) *GenerationError {
+	var rateErr *client.RateLimitError
+	switch {
+	case errors.As(err, &rateErr):
+		retryAt := time.Now().Add(rateErr.RetryAfterDuration()).UTC()
+		return &GenerationError{
+			Code:    ErrorCodeRateLimited,
+			Message: "github rate limit exceeded, retry after " + retryAt.Format(time.RFC3339),
+			RetryAt: &retryAt,
+		}
+	case errors.Is(err, client.ErrAppNotInstalled):
+		return &GenerationError{Code: ErrorCodeAppNotInstalled, Message: "github app is not installed on your account"}
+	case errors.Is(err, ErrGenerationInProgress):
+		return &GenerationError{Code: ErrorCodeInProgress, Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &GenerationError{Code: ErrorCodeTimeout, Message: "generation timed out"}
+	default:
+		return &GenerationError{Code: ErrorCodeInternal, Message: "failed to generate resume"}
+	}
+}
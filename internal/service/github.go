@@ -3,30 +3,84 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/yourusername/resume-builder/internal/client"
 	"github.com/yourusername/resume-builder/internal/model"
 )
 
+// GitHubFetcher retrieves the GitHub data a resume is built from. It is
+// implemented by both the REST and the GraphQL clients.
+type GitHubFetcher interface {
+	GetUserData(ctx context.Context, token, login string) (*model.GitHubProfile, []model.Repository, error)
+	GetContributions(ctx context.Context, token, login string) ([]model.Contribution, error)
+	GetLanguages(ctx context.Context, token, fullName string) (map[string]int, error)
+	GetManifests(ctx context.Context, token, fullName string, paths []string) (map[string][]byte, error)
+	GetReadme(ctx context.Context, token, fullName string) (string, error)
+	GetAuthorship(ctx context.Context, token, fullName, login string) (*model.Authorship, error)
+}
+
+// SnapshotStore keeps each user's latest GitHub data. It is implemented by
+// repository.SnapshotRepository.
+type SnapshotStore interface {
+	Save(ctx context.Context, login string, profile *model.GitHubProfile, repos []model.Repository) error
+	GetByLogin(ctx context.Context, login string) (*model.GitHubSnapshot, error)
+}
+
+// FetchStore records each fetch of a user's repositories. It is implemented
+// by repository.RepoRepository.
+type FetchStore interface {
+	SaveFetch(ctx context.Context, userID int64, repos []model.Repository, fetchedAt time.Time) error
+	GetLatest(ctx context.Context, userID int64) ([]model.StoredRepository, error)
+	PruneFetches(ctx context.Context, userID int64, keep int) error
+}
+
+// maxStoredFetches is how many fetches of a user's repositories are kept in
+// the repositories table.
+const maxStoredFetches = 30
+
 type GitHubService struct {
-	client *client.GitHubClient
-	cache  *client.CacheClient
+	client    GitHubFetcher
+	cache     *client.CacheClient
+	snapshots SnapshotStore
+	repos     FetchStore
+	// snapshotMaxAge is how long after a full fetch a webhook-maintained
+	// snapshot is trusted over GitHub. Zero only uses snapshots when GitHub
+	// can't be reached.
+	snapshotMaxAge time.Duration
 }
 
-func NewGitHubService(ghClient *client.GitHubClient, cache *client.CacheClient) *GitHubService {
+func NewGitHubService(fetcher GitHubFetcher, cache *client.CacheClient, snapshots SnapshotStore, repos FetchStore, snapshotMaxAge time.Duration) *GitHubService {
 	return &GitHubService{
-		client: ghClient,
-		cache:  cache,
+		client:         fetcher,
+		cache:          cache,
+		snapshots:      snapshots,
+		repos:          repos,
+		snapshotMaxAge: snapshotMaxAge,
 	}
 }
 
-func (s *GitHubService) FetchUserData(ctx context.Context, token string) (*model.GitHubProfile, []model.Repository, error) {
-	cacheKey := fmt.Sprintf("github:repos:%s", token[:10])
+// FetchUserData returns the user's profile and repositories, from their
+// snapshot while it's fresh, otherwise from GitHub cached for an hour. login
+// identifies the user to installation tokens. Every fetch from GitHub is
+// saved as the user's snapshot, which is used regardless of its age when
+// GitHub can't be reached.
+func (s *GitHubService) FetchUserData(ctx context.Context, userID int64, token, login string) (*model.GitHubProfile, []model.Repository, error) {
+	if s.snapshotMaxAge > 0 {
+		if snapshot := s.storedSnapshot(ctx, login); snapshot != nil && time.Since(snapshot.FetchedAt) < s.snapshotMaxAge {
+			return &snapshot.Profile, snapshot.Repositories, nil
+		}
+	}
 
 	// Try cache first
-	if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
+	if cached, err := s.cache.Get(ctx, userDataCacheKey(userID)); err == nil {
 		var data struct {
 			Profile *model.GitHubProfile
 			Repos   []model.Repository
@@ -36,41 +90,420 @@ func (s *GitHubService) FetchUserData(ctx context.Context, token string) (*model
 		}
 	}
 
-	profile, err := s.client.GetProfile(ctx, token)
+	profile, repos, err := s.fetchUserData(ctx, userID, token, login)
 	if err != nil {
+		if snapshot := s.storedSnapshot(ctx, login); snapshot != nil {
+			slog.Warn("failed to fetch github data, using snapshot", "error", err, "user_id", userID)
+			return &snapshot.Profile, snapshot.Repositories, nil
+		}
 		return nil, nil, err
 	}
 
-	repos, err := s.client.GetRepositories(ctx, token)
+	return profile, repos, nil
+}
+
+// RefreshUserData fetches the user's profile and repositories from GitHub,
+// bypassing snapshots and the cache, and reports how the repositories changed
+// since the latest stored fetch. The changes are nil if there was none.
+func (s *GitHubService) RefreshUserData(ctx context.Context, userID int64, token, login string) (*model.GitHubChanges, error) {
+	previous, err := s.repos.GetLatest(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored repositories: %w", err)
+	}
+
+	_, repos, err := s.fetchUserData(ctx, userID, token, login)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(previous) == 0 {
+		return nil, nil
+	}
+	return compareRepositories(previous, repos), nil
+}
+
+// fetchUserData fetches the user's profile and repositories from GitHub,
+// saving them as the user's snapshot, recording the repositories and caching
+// them for an hour.
+func (s *GitHubService) fetchUserData(ctx context.Context, userID int64, token, login string) (*model.GitHubProfile, []model.Repository, error) {
+	profile, repos, err := s.client.GetUserData(ctx, token, login)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Cache for 1 hour
+	s.storeFetch(ctx, userID, repos)
+
+	if err := s.snapshots.Save(ctx, login, profile, repos); err != nil {
+		slog.Warn("failed to save github snapshot", "error", err, "login", login)
+	}
+
 	if data, err := json.Marshal(map[string]interface{}{"Profile": profile, "Repos": repos}); err == nil {
-		s.cache.Set(ctx, cacheKey, string(data), time.Hour)
+		s.cache.Set(ctx, userDataCacheKey(userID), string(data), time.Hour)
 	}
 
 	return profile, repos, nil
 }
 
-func (s *GitHubService) ExtractSkills(repos []model.Repository) []string {
-	skillSet := make(map[string]bool)
+func userDataCacheKey(userID int64) string {
+	return fmt.Sprintf("github:repos:%d", userID)
+}
+
+func compareRepositories(previous []model.StoredRepository, current []model.Repository) *model.GitHubChanges {
+	changes := &model.GitHubChanges{Since: previous[0].FetchedAt}
+
+	before := make(map[string]model.Repository, len(previous))
+	for _, repo := range previous {
+		before[strings.ToLower(repo.FullName)] = repo.Repository
+	}
+
+	for _, repo := range current {
+		key := strings.ToLower(repo.FullName)
+		old, ok := before[key]
+		if !ok {
+			changes.Added = append(changes.Added, repo.FullName)
+			continue
+		}
+		delete(before, key)
+
+		if repo.LastCommitDate.After(old.LastCommitDate) {
+			changes.Pushed = append(changes.Pushed, repo.FullName)
+		}
+		changes.StarsGained += repo.Stars - old.Stars
+	}
+
+	for _, repo := range before {
+		changes.Removed = append(changes.Removed, repo.FullName)
+	}
+	sort.Strings(changes.Removed)
+
+	return changes
+}
+
+// storeFetch records a fetch of the user's repositories, dropping the oldest
+// fetches beyond maxStoredFetches.
+func (s *GitHubService) storeFetch(ctx context.Context, userID int64, repos []model.Repository) {
+	if err := s.repos.SaveFetch(ctx, userID, repos, time.Now().UTC()); err != nil {
+		slog.Warn("failed to store repositories", "error", err, "user_id", userID)
+		return
+	}
+	if err := s.repos.PruneFetches(ctx, userID, maxStoredFetches); err != nil {
+		slog.Warn("failed to prune stored repositories", "error", err, "user_id", userID)
+	}
+}
+
+// storedSnapshot returns the user's snapshot, or nil if there is none.
+func (s *GitHubService) storedSnapshot(ctx context.Context, login string) *model.GitHubSnapshot {
+	snapshot, err := s.snapshots.GetByLogin(ctx, login)
+	if err != nil {
+		slog.Warn("failed to read github snapshot", "error", err, "login", login)
+		return nil
+	}
+	return snapshot
+}
+
+// FetchContributions returns repositories the user contributes to without
+// owning them, cached alongside the user's own repositories.
+func (s *GitHubService) FetchContributions(ctx context.Context, token, login string) ([]model.Contribution, error) {
+	cacheKey := fmt.Sprintf("github:contributions:%s", strings.ToLower(login))
+
+	if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
+		var contributions []model.Contribution
+		if json.Unmarshal([]byte(cached), &contributions) == nil {
+			return contributions, nil
+		}
+	}
+
+	contributions, err := s.client.GetContributions(ctx, token, login)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(contributions); err == nil {
+		s.cache.Set(ctx, cacheKey, string(data), time.Hour)
+	}
+
+	return contributions, nil
+}
+
+// FetchLanguages fills in the per-language byte counts of repositories that
+// don't carry them yet, requesting up to four repositories at a time.
+func (s *GitHubService) FetchLanguages(ctx context.Context, token string, repos []model.RankedRepository) error {
+	errs := make([]error, len(repos))
+	sem := make(chan struct{}, 4)
+
+	var wg sync.WaitGroup
+	for i := range repos {
+		if repos[i].Languages != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(repo *model.RankedRepository, err *error) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			cacheKey := fmt.Sprintf("github:languages:%s", repo.FullName)
+			if cached, cacheErr := s.cache.Get(ctx, cacheKey); cacheErr == nil {
+				if json.Unmarshal([]byte(cached), &repo.Languages) == nil {
+					return
+				}
+			}
+
+			languages, fetchErr := s.client.GetLanguages(ctx, token, repo.FullName)
+			if fetchErr != nil {
+				*err = fetchErr
+				return
+			}
+			repo.Languages = languages
+
+			if data, marshalErr := json.Marshal(languages); marshalErr == nil {
+				s.cache.Set(ctx, cacheKey, string(data), time.Hour)
+			}
+		}(&repos[i], &errs[i])
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchFrameworks detects frameworks and tools from the dependency manifests
+// of the given repositories, requesting up to four repositories at a time.
+func (s *GitHubService) FetchFrameworks(ctx context.Context, token string, repos []model.RankedRepository) error {
+	errs := make([]error, len(repos))
+	sem := make(chan struct{}, 4)
+
+	var wg sync.WaitGroup
+	for i := range repos {
+		wg.Add(1)
+		go func(repo *model.RankedRepository, err *error) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			cacheKey := fmt.Sprintf("github:frameworks:%s", repo.FullName)
+			if cached, cacheErr := s.cache.Get(ctx, cacheKey); cacheErr == nil {
+				if json.Unmarshal([]byte(cached), &repo.Frameworks) == nil {
+					return
+				}
+			}
+
+			manifests, fetchErr := s.client.GetManifests(ctx, token, repo.FullName, manifestFiles)
+			if fetchErr != nil {
+				*err = fetchErr
+				return
+			}
+
+			frameworks := []model.Framework{}
+			for _, path := range manifestFiles {
+				if content, ok := manifests[path]; ok {
+					for _, name := range detectFrameworks(path, content) {
+						frameworks = append(frameworks, model.Framework{Name: name, Manifest: path})
+					}
+				}
+			}
+			repo.Frameworks = frameworks
+
+			if data, marshalErr := json.Marshal(frameworks); marshalErr == nil {
+				s.cache.Set(ctx, cacheKey, string(data), time.Hour)
+			}
+		}(&repos[i], &errs[i])
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchReadmes fills in the sanitized README of the given repositories,
+// requesting up to four repositories at a time.
+func (s *GitHubService) FetchReadmes(ctx context.Context, token string, repos []model.RankedRepository) error {
+	errs := make([]error, len(repos))
+	sem := make(chan struct{}, 4)
+
+	var wg sync.WaitGroup
+	for i := range repos {
+		wg.Add(1)
+		go func(repo *model.RankedRepository, err *error) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			cacheKey := fmt.Sprintf("github:readme:%s", repo.FullName)
+			if cached, cacheErr := s.cache.Get(ctx, cacheKey); cacheErr == nil {
+				repo.Readme = cached
+				return
+			}
+
+			raw, fetchErr := s.client.GetReadme(ctx, token, repo.FullName)
+			if fetchErr != nil {
+				*err = fetchErr
+				return
+			}
+			repo.Readme = sanitizeReadme(raw)
+
+			s.cache.Set(ctx, cacheKey, repo.Readme, time.Hour)
+		}(&repos[i], &errs[i])
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchAuthorship returns the user's share of the commits of the given
+// repositories, keyed by full name, requesting up to four repositories at a
+// time. Repositories whose statistics GitHub is still computing are left out.
+func (s *GitHubService) FetchAuthorship(ctx context.Context, token, login string, repos []model.RankedRepository) (map[string]*model.Authorship, error) {
+	results := make([]*model.Authorship, len(repos))
+	errs := make([]error, len(repos))
+	sem := make(chan struct{}, 4)
+
+	var wg sync.WaitGroup
+	for i := range repos {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fullName := repos[i].FullName
+			cacheKey := fmt.Sprintf("github:authorship:%s:%s", fullName, login)
+			if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
+				if json.Unmarshal([]byte(cached), &results[i]) == nil {
+					return
+				}
+			}
+
+			authorship, err := s.client.GetAuthorship(ctx, token, fullName, login)
+			if errors.Is(err, client.ErrStatsPending) {
+				return
+			}
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i] = authorship
+
+			if data, err := json.Marshal(authorship); err == nil {
+				s.cache.Set(ctx, cacheKey, string(data), time.Hour)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	authorship := make(map[string]*model.Authorship)
+	for i, result := range results {
+		if result != nil {
+			authorship[repos[i].FullName] = result
+		}
+	}
+
+	return authorship, nil
+}
+
+// maxSkillSources caps how many sources are recorded per skill.
+const maxSkillSources = 5
+
+// SkillSet is the skills found across a user's repositories.
+type SkillSet struct {
+	Skills []string
+	// Weights maps languages to their share of the code in the candidates.
+	Weights map[string]float64
+	// Sources records where each skill was found.
+	Sources map[string][]string
+}
+
+// ExtractSkills collects languages and topics across all repositories, and
+// language byte counts and manifest frameworks from the candidates, which
+// should already have both fetched. Skills are ordered by language weight,
+// then by how many candidates use a framework, then alphabetically.
+func (s *GitHubService) ExtractSkills(repos []model.Repository, candidates []model.RankedRepository) SkillSet {
+	set := SkillSet{
+		Weights: make(map[string]float64),
+		Sources: make(map[string][]string),
+	}
+
+	addSource := func(skill, source string) {
+		sources := set.Sources[skill]
+		if len(sources) >= maxSkillSources {
+			return
+		}
+		for _, existing := range sources {
+			if existing == source {
+				return
+			}
+		}
+		set.Sources[skill] = append(sources, source)
+	}
 
 	for _, repo := range repos {
 		if repo.Language != "" {
-			skillSet[repo.Language] = true
+			addSource(repo.Language, repo.FullName+":language")
 		}
 
 		for _, topic := range repo.Topics {
-			skillSet[topic] = true
+			addSource(topic, repo.FullName+":topic")
+		}
+	}
+
+	bytesByLanguage := make(map[string]int)
+	frameworkUsage := make(map[string]int)
+	var total int
+	for _, candidate := range candidates {
+		for language, size := range candidate.Languages {
+			bytesByLanguage[language] += size
+			total += size
+			addSource(language, candidate.FullName+":language")
+		}
+
+		for _, framework := range candidate.Frameworks {
+			frameworkUsage[framework.Name]++
+			addSource(framework.Name, candidate.FullName+":"+framework.Manifest)
 		}
 	}
 
-	skills := make([]string, 0, len(skillSet))
-	for skill := range skillSet {
-		skills = append(skills, skill)
+	for language, size := range bytesByLanguage {
+		set.Weights[language] = math.Round(float64(size)/float64(total)*1000) / 1000
+	}
+
+	set.Skills = make([]string, 0, len(set.Sources))
+	for skill := range set.Sources {
+		set.Skills = append(set.Skills, skill)
 	}
 
-	return skills
+	sort.Slice(set.Skills, func(i, j int) bool {
+		a, b := set.Skills[i], set.Skills[j]
+		if set.Weights[a] != set.Weights[b] {
+			return set.Weights[a] > set.Weights[b]
+		}
+		if frameworkUsage[a] != frameworkUsage[b] {
+			return frameworkUsage[a] > frameworkUsage[b]
+		}
+		return a < b
+	})
+
+	return set
 }
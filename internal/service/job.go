@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+	"github.com/yourusername/resume-builder/internal/repository"
+)
+
+const (
+	// jobPollInterval is how often idle workers look for jobs queued on
+	// other replicas.
+	jobPollInterval = 2 * time.Second
+	// jobStaleAfter is how long a running job may go without progress before
+	// it is assumed abandoned and claimed again.
+	jobStaleAfter = 10 * time.Minute
+	// maxJobAttempts bounds how often an abandoned job is started again.
+	maxJobAttempts = 3
+	jobTimeout     = 5 * time.Minute
+)
+
+// ErrGenerationInProgress is returned when queueing a generation for a user
+// who already has one queued or running.
+var ErrGenerationInProgress = errors.New("a resume generation is already in progress")
+
+// JobService runs resume generation as background jobs on a pool of
+// workers. Jobs are queued in Postgres, so any replica's workers can run
+// them.
+type JobService struct {
+	jobRepo       *repository.JobRepository
+	authService   *AuthService
+	resumeService *ResumeService
+	workers       int
+	// wake signals idle workers that a job was queued by this replica.
+	wake chan struct{}
+}
+
+func NewJobService(jobRepo *repository.JobRepository, authService *AuthService, resumeService *ResumeService, workers int) *JobService {
+	return &JobService{
+		jobRepo:       jobRepo,
+		authService:   authService,
+		resumeService: resumeService,
+		workers:       workers,
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Enqueue queues a resume generation for the user. Users get one generation
+// at a time: if one is already queued or running, it is returned along with
+// ErrGenerationInProgress.
+func (s *JobService) Enqueue(ctx context.Context, userID int64, opts GenerateOptions) (*model.GenerationJob, error) {
+	job := &model.GenerationJob{
+		UserID:     userID,
+		TargetRole: opts.TargetRole,
+		PinnedOnly: opts.PinnedOnly,
+	}
+	created, err := s.jobRepo.Create(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		pending, err := s.jobRepo.GetPending(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		// The pending job may have finished in the meantime.
+		if pending == nil {
+			return s.Enqueue(ctx, userID, opts)
+		}
+		return pending, ErrGenerationInProgress
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// Generate runs a resume generation for the user in the caller's goroutine
+// rather than on a worker, for callers streaming its progress. It still
+// counts as the user's one generation in progress, so it fails with
+// ErrGenerationInProgress while another is queued or running.
+func (s *JobService) Generate(ctx context.Context, userID int64, token string, opts GenerateOptions) (*model.Resume, error) {
+	job := &model.GenerationJob{
+		UserID:     userID,
+		TargetRole: opts.TargetRole,
+		PinnedOnly: opts.PinnedOnly,
+	}
+	started, err := s.jobRepo.Start(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, ErrGenerationInProgress
+	}
+
+	// Progress also shows the job is alive, so it isn't claimed as abandoned.
+	progress := opts.Progress
+	opts.Progress = func(event GenerationEvent) {
+		if err := s.jobRepo.UpdateProgress(ctx, job.ID, event.Stage, event.Progress); err != nil {
+			slog.Warn("failed to update generation job progress", "error", err, "job_id", job.ID)
+		}
+		if progress != nil {
+			progress(event)
+		}
+	}
+
+	resume, err := s.resumeService.GenerateResume(ctx, userID, token, opts)
+	if err != nil {
+		s.fail(job, DescribeGenerationError(err), err)
+		return nil, err
+	}
+
+	if err := s.jobRepo.Complete(context.Background(), job.ID, resume.ID); err != nil {
+		slog.Error("failed to complete generation job", "error", err, "job_id", job.ID)
+	}
+	return resume, nil
+}
+
+func (s *JobService) GetJob(ctx context.Context, jobID, userID int64) (*model.GenerationJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	if job.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	return job, nil
+}
+
+// Run starts the workers and blocks until ctx is cancelled and they have
+// stopped. Jobs interrupted by the cancellation are queued again.
+func (s *JobService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *JobService) work(ctx context.Context) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := s.jobRepo.ClaimNext(ctx, time.Now().Add(-jobStaleAfter))
+		if err != nil && ctx.Err() == nil {
+			slog.Error("failed to claim generation job", "error", err)
+		}
+		if job != nil {
+			s.execute(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *JobService) execute(ctx context.Context, job *model.GenerationJob) {
+	if job.Attempts > maxJobAttempts {
+		s.fail(job, &GenerationError{Code: ErrorCodeInternal, Message: "generation was interrupted too many times"}, nil)
+		return
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	resumeID, err := s.generate(jobCtx, job)
+	if err == nil {
+		if err := s.jobRepo.Complete(context.Background(), job.ID, resumeID); err != nil {
+			slog.Error("failed to complete generation job", "error", err, "job_id", job.ID)
+		}
+		return
+	}
+
+	// A shutdown isn't the job's fault, so it's left for another worker.
+	if ctx.Err() != nil {
+		if err := s.jobRepo.Requeue(context.Background(), job.ID); err != nil {
+			slog.Error("failed to requeue generation job", "error", err, "job_id", job.ID)
+		}
+		return
+	}
+
+	s.fail(job, DescribeGenerationError(err), err)
+}
+
+func (s *JobService) generate(ctx context.Context, job *model.GenerationJob) (int64, error) {
+	token, err := s.authService.GetUserToken(ctx, job.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	resume, err := s.resumeService.GenerateResume(ctx, job.UserID, token, GenerateOptions{
+		TargetRole: job.TargetRole,
+		PinnedOnly: job.PinnedOnly,
+		Progress: func(event GenerationEvent) {
+			if err := s.jobRepo.UpdateProgress(ctx, job.ID, event.Stage, event.Progress); err != nil {
+				slog.Warn("failed to update generation job progress", "error", err, "job_id", job.ID)
+			}
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return resume.ID, nil
+}
+
+func (s *JobService) fail(job *model.GenerationJob, failure *GenerationError, cause error) {
+	slog.Warn("generation job failed", "error", cause, "job_id", job.ID, "user_id", job.UserID)
+	if err := s.jobRepo.Fail(context.Background(), job.ID, failure.Message, failure.RetryAt); err != nil {
+		slog.Error("failed to record generation job failure", "error", err, "job_id", job.ID)
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/export"
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+const (
+	jsonResumeSchemaURL = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+	jsonResumeVersion   = "v1.0.0"
+)
+
+var ErrInvalidJSONResume = errors.New("invalid json resume")
+
+// iso8601Date is the date pattern used throughout the JSON Resume schema.
+var iso8601Date = regexp.MustCompile(`^([1-2][0-9]{3}-[0-1][0-9]-[0-3][0-9]|[1-2][0-9]{3}-[0-1][0-9]|[1-2][0-9]{3})$`)
+
+func (s *ResumeService) ExportJSONResume(ctx context.Context, resumeID, userID int64) (*model.JSONResume, error) {
+	resume, err := s.GetResume(ctx, resumeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	doc := &model.JSONResume{
+		Schema: jsonResumeSchemaURL,
+		Basics: model.JSONResumeBasics{
+			Name:    user.Name,
+			Label:   resume.TargetRole,
+			Image:   user.AvatarURL,
+			Email:   user.Email,
+			Summary: resume.Summary,
+			Profiles: []model.JSONResumeProfile{{
+				Network:  "GitHub",
+				Username: user.Username,
+				URL:      strings.TrimRight(s.githubWebURL, "/") + "/" + user.Username,
+			}},
+		},
+		Meta: &model.JSONResumeMeta{
+			Version:      jsonResumeVersion,
+			LastModified: resume.UpdatedAt.UTC().Format(time.RFC3339),
+		},
+	}
+
+	for _, skill := range resume.Skills {
+		doc.Skills = append(doc.Skills, model.JSONResumeSkill{Name: skill})
+	}
+
+	for _, project := range export.SortedProjects(resume) {
+		doc.Projects = append(doc.Projects, model.JSONResumeProject{
+			Name:        project.RepoName,
+			Description: project.Description,
+			Highlights:  project.Highlights,
+			Keywords:    project.Topics,
+			URL:         project.URL,
+			Language:    project.Language,
+			Stars:       project.Stars,
+		})
+	}
+
+	return doc, nil
+}
+
+func (s *ResumeService) ImportJSONResume(ctx context.Context, userID int64, doc *model.JSONResume) (*model.Resume, error) {
+	if err := validateJSONResume(doc); err != nil {
+		return nil, err
+	}
+
+	resume := &model.Resume{
+		UserID:     userID,
+		Title:      "Imported Resume",
+		TargetRole: doc.Basics.Label,
+		Summary:    doc.Basics.Summary,
+		Projects:   make([]model.ResumeProject, 0, len(doc.Projects)),
+		Skills:     []string{},
+	}
+
+	seen := make(map[string]bool)
+	addSkill := func(skill string) {
+		if skill != "" && !seen[skill] {
+			seen[skill] = true
+			resume.Skills = append(resume.Skills, skill)
+		}
+	}
+	for _, skill := range doc.Skills {
+		addSkill(skill.Name)
+		for _, keyword := range skill.Keywords {
+			addSkill(keyword)
+		}
+	}
+
+	for i, project := range doc.Projects {
+		resume.Projects = append(resume.Projects, model.ResumeProject{
+			RepoName:    project.Name,
+			Description: project.Description,
+			URL:         project.URL,
+			Stars:       project.Stars,
+			Language:    project.Language,
+			Topics:      project.Keywords,
+			Highlights:  project.Highlights,
+			Position:    i,
+		})
+	}
+
+	if err := s.resumeRepo.Create(ctx, resume); err != nil {
+		return nil, err
+	}
+
+	return resume, nil
+}
+
+// validateJSONResume checks the formats the JSON Resume schema constrains
+// (emails, URIs and ISO 8601 dates) along with the fields the builder needs.
+func validateJSONResume(doc *model.JSONResume) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidJSONResume, fmt.Sprintf(format, args...))
+	}
+
+	if doc.Basics.Email != "" {
+		if _, err := mail.ParseAddress(doc.Basics.Email); err != nil {
+			return invalid("basics.email is not a valid email address")
+		}
+	}
+
+	uris := map[string]string{
+		"basics.url":   doc.Basics.URL,
+		"basics.image": doc.Basics.Image,
+	}
+	for i, profile := range doc.Basics.Profiles {
+		uris[fmt.Sprintf("basics.profiles[%d].url", i)] = profile.URL
+	}
+	for i, work := range doc.Work {
+		uris[fmt.Sprintf("work[%d].url", i)] = work.URL
+	}
+	for i, education := range doc.Education {
+		uris[fmt.Sprintf("education[%d].url", i)] = education.URL
+	}
+	for i, project := range doc.Projects {
+		uris[fmt.Sprintf("projects[%d].url", i)] = project.URL
+	}
+	for _, field := range sortedKeys(uris) {
+		value := uris[field]
+		if value == "" {
+			continue
+		}
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" {
+			return invalid("%s is not a valid uri", field)
+		}
+	}
+
+	dates := map[string]string{}
+	for i, work := range doc.Work {
+		dates[fmt.Sprintf("work[%d].startDate", i)] = work.StartDate
+		dates[fmt.Sprintf("work[%d].endDate", i)] = work.EndDate
+	}
+	for i, education := range doc.Education {
+		dates[fmt.Sprintf("education[%d].startDate", i)] = education.StartDate
+		dates[fmt.Sprintf("education[%d].endDate", i)] = education.EndDate
+	}
+	for i, project := range doc.Projects {
+		dates[fmt.Sprintf("projects[%d].startDate", i)] = project.StartDate
+		dates[fmt.Sprintf("projects[%d].endDate", i)] = project.EndDate
+	}
+	for _, field := range sortedKeys(dates) {
+		value := dates[field]
+		if value != "" && !iso8601Date.MatchString(value) {
+			return invalid("%s must be an ISO 8601 date (YYYY, YYYY-MM or YYYY-MM-DD)", field)
+		}
+	}
+
+	for i, project := range doc.Projects {
+		if project.Name == "" {
+			return invalid("projects[%d].name is required", i)
+		}
+		if project.Stars < 0 {
+			return invalid("projects[%d].stars must not be negative", i)
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns the fields of m in order, so the first invalid field
+// reported is the same on every run.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
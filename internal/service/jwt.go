@@ -7,12 +7,22 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	// streamTokenScope marks tokens that only authenticate event streams.
+	streamTokenScope = "stream"
+	// StreamTokenTTL is how long a stream token is valid. It travels in the
+	// URL, so it's kept short.
+	StreamTokenTTL = time.Minute
+)
+
 type JWTService struct {
 	secret []byte
 }
 
 type Claims struct {
 	UserID int64 `json:"user_id"`
+	// Scope restricts what the token authenticates; session tokens have none.
+	Scope string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -21,10 +31,29 @@ func NewJWTService(secret string) *JWTService {
 }
 
 func (s *JWTService) GenerateToken(userID int64) (string, error) {
+	return s.generate(userID, "", 24*time.Hour)
+}
+
+// GenerateStreamToken returns a token valid for StreamTokenTTL that only
+// authenticates event streams, for clients that must pass it in the URL.
+func (s *JWTService) GenerateStreamToken(userID int64) (string, error) {
+	return s.generate(userID, streamTokenScope, StreamTokenTTL)
+}
+
+func (s *JWTService) ValidateToken(tokenString string) (int64, error) {
+	return s.validate(tokenString, "")
+}
+
+func (s *JWTService) ValidateStreamToken(tokenString string) (int64, error) {
+	return s.validate(tokenString, streamTokenScope)
+}
+
+func (s *JWTService) generate(userID int64, scope string, ttl time.Duration) (string, error) {
 	claims := Claims{
 		UserID: userID,
+		Scope:  scope,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -33,7 +62,7 @@ func (s *JWTService) GenerateToken(userID int64) (string, error) {
 	return token.SignedString(s.secret)
 }
 
-func (s *JWTService) ValidateToken(tokenString string) (int64, error) {
+func (s *JWTService) validate(tokenString, scope string) (int64, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method")
@@ -46,6 +75,9 @@ func (s *JWTService) ValidateToken(tokenString string) (int64, error) {
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		if claims.Scope != scope {
+			return 0, fmt.Errorf("token scope %q not accepted here", claims.Scope)
+		}
 		return claims.UserID, nil
 	}
 
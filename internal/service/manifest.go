@@ -0,0 +1,357 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// manifestFiles are the root-level dependency manifests inspected for
+// frameworks and tools.
+var manifestFiles = []string{
+	"go.mod",
+	"package.json",
+	"requirements.txt",
+	"pyproject.toml",
+	"Cargo.toml",
+	"pom.xml",
+	"Dockerfile",
+}
+
+// Dependency names are matched exactly, or as a prefix when the key ends
+// in "/" (Go module paths, npm scopes).
+var goFrameworks = map[string]string{
+	"github.com/gin-gonic/gin":                    "Gin",
+	"github.com/go-chi/chi/":                      "chi",
+	"github.com/labstack/echo/":                   "Echo",
+	"github.com/gofiber/fiber/":                   "Fiber",
+	"github.com/gorilla/mux":                      "Gorilla",
+	"gorm.io/gorm":                                "GORM",
+	"github.com/spf13/cobra":                      "Cobra",
+	"google.golang.org/grpc":                      "gRPC",
+	"k8s.io/client-go":                            "Kubernetes",
+	"sigs.k8s.io/controller-runtime":              "Kubernetes",
+	"github.com/redis/go-redis/":                  "Redis",
+	"github.com/go-redis/redis/":                  "Redis",
+	"github.com/lib/pq":                           "PostgreSQL",
+	"github.com/jackc/pgx/":                       "PostgreSQL",
+	"go.mongodb.org/mongo-driver":                 "MongoDB",
+	"github.com/prometheus/client_golang":         "Prometheus",
+	"go.opentelemetry.io/otel":                    "OpenTelemetry",
+	"github.com/aws/aws-sdk-go":                   "AWS",
+	"github.com/aws/aws-sdk-go-v2":                "AWS",
+	"github.com/segmentio/kafka-go":               "Kafka",
+	"github.com/confluentinc/confluent-kafka-go/": "Kafka",
+	"github.com/stretchr/testify":                 "Testify",
+}
+
+var npmFrameworks = map[string]string{
+	"react":            "React",
+	"react-native":     "React Native",
+	"next":             "Next.js",
+	"vue":              "Vue",
+	"nuxt":             "Nuxt",
+	"@angular/core":    "Angular",
+	"svelte":           "Svelte",
+	"express":          "Express",
+	"fastify":          "Fastify",
+	"@nestjs/core":     "NestJS",
+	"typescript":       "TypeScript",
+	"tailwindcss":      "Tailwind CSS",
+	"redux":            "Redux",
+	"@reduxjs/toolkit": "Redux",
+	"graphql":          "GraphQL",
+	"@apollo/client":   "Apollo",
+	"prisma":           "Prisma",
+	"mongoose":         "MongoDB",
+	"electron":         "Electron",
+	"jest":             "Jest",
+	"vitest":           "Vitest",
+	"vite":             "Vite",
+	"webpack":          "webpack",
+	"socket.io":        "Socket.IO",
+}
+
+var pythonFrameworks = map[string]string{
+	"django":       "Django",
+	"flask":        "Flask",
+	"fastapi":      "FastAPI",
+	"numpy":        "NumPy",
+	"pandas":       "pandas",
+	"torch":        "PyTorch",
+	"tensorflow":   "TensorFlow",
+	"scikit-learn": "scikit-learn",
+	"sqlalchemy":   "SQLAlchemy",
+	"celery":       "Celery",
+	"pytest":       "pytest",
+	"pydantic":     "Pydantic",
+	"boto3":        "AWS",
+	"kubernetes":   "Kubernetes",
+}
+
+var rustFrameworks = map[string]string{
+	"tokio":     "Tokio",
+	"actix-web": "Actix Web",
+	"axum":      "Axum",
+	"rocket":    "Rocket",
+	"serde":     "Serde",
+	"diesel":    "Diesel",
+	"sqlx":      "SQLx",
+	"bevy":      "Bevy",
+	"tauri":     "Tauri",
+	"kube":      "Kubernetes",
+}
+
+// javaFrameworks is keyed by groupId or groupId:artifactId.
+var javaFrameworks = map[string]string{
+	"org.springframework.boot":  "Spring Boot",
+	"org.springframework":       "Spring",
+	"org.hibernate":             "Hibernate",
+	"org.hibernate.orm":         "Hibernate",
+	"org.junit.jupiter":         "JUnit",
+	"junit":                     "JUnit",
+	"org.apache.kafka":          "Kafka",
+	"io.quarkus":                "Quarkus",
+	"io.micronaut":              "Micronaut",
+	"io.fabric8":                "Kubernetes",
+	"org.postgresql:postgresql": "PostgreSQL",
+}
+
+// detectFrameworks maps the dependencies declared in a manifest to the
+// frameworks and tools they indicate, sorted by name.
+func detectFrameworks(path string, content []byte) []string {
+	var names []string
+	var table map[string]string
+
+	switch path {
+	case "go.mod":
+		names, table = goModDependencies(content), goFrameworks
+	case "package.json":
+		names, table = packageJSONDependencies(content), npmFrameworks
+	case "requirements.txt":
+		names, table = requirementsDependencies(content), pythonFrameworks
+	case "pyproject.toml":
+		names, table = tomlDependencies(content, true), pythonFrameworks
+	case "Cargo.toml":
+		names, table = tomlDependencies(content, false), rustFrameworks
+	case "pom.xml":
+		names, table = pomDependencies(content), javaFrameworks
+	case "Dockerfile":
+		return []string{"Docker"}
+	default:
+		return nil
+	}
+
+	found := make(map[string]bool)
+	for _, name := range names {
+		if framework, ok := matchDependency(name, table); ok {
+			found[framework] = true
+		}
+	}
+
+	frameworks := make([]string, 0, len(found))
+	for framework := range found {
+		frameworks = append(frameworks, framework)
+	}
+	sort.Strings(frameworks)
+
+	return frameworks
+}
+
+func matchDependency(name string, table map[string]string) (string, bool) {
+	if framework, ok := table[name]; ok {
+		return framework, true
+	}
+	for key, framework := range table {
+		if strings.HasSuffix(key, "/") && strings.HasPrefix(name, key) {
+			return framework, true
+		}
+	}
+	// Go modules with a major version suffix, e.g. github.com/x/y/v5.
+	if i := strings.LastIndex(name, "/v"); i > 0 {
+		if framework, ok := table[name[:i]]; ok {
+			return framework, true
+		}
+	}
+	return "", false
+}
+
+func goModDependencies(content []byte) []string {
+	var deps []string
+	inBlock := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripComment(scanner.Text(), "//"))
+		switch {
+		case line == "require (":
+			inBlock = true
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			if fields := strings.Fields(line); len(fields) > 0 {
+				deps = append(deps, fields[0])
+			}
+		case strings.HasPrefix(line, "require "):
+			if fields := strings.Fields(line); len(fields) > 1 {
+				deps = append(deps, fields[1])
+			}
+		}
+	}
+
+	return deps
+}
+
+func packageJSONDependencies(content []byte) []string {
+	var pkg struct {
+		Dependencies     map[string]string `json:"dependencies"`
+		DevDependencies  map[string]string `json:"devDependencies"`
+		PeerDependencies map[string]string `json:"peerDependencies"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil
+	}
+
+	var deps []string
+	for _, group := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies} {
+		for name := range group {
+			deps = append(deps, name)
+		}
+	}
+	return deps
+}
+
+func requirementsDependencies(content []byte) []string {
+	var deps []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripComment(scanner.Text(), "#"))
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		deps = append(deps, pythonPackageName(line))
+	}
+
+	return deps
+}
+
+// tomlDependencies extracts dependency names from pyproject.toml or
+// Cargo.toml without a full TOML parser: keys of any *dependencies table,
+// [dependencies.<name>] headers and, for PEP 621 projects, the quoted
+// requirement strings of dependency arrays.
+func tomlDependencies(content []byte, python bool) []string {
+	var deps []string
+	inTable, inArray := false, false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripComment(scanner.Text(), "#"))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			header := strings.Trim(line, "[] ")
+			inTable, inArray = false, false
+			if strings.HasSuffix(header, "dependencies") {
+				inTable = true
+			} else if i := strings.LastIndex(header, "dependencies."); i >= 0 {
+				deps = append(deps, header[i+len("dependencies."):])
+			}
+			continue
+		}
+
+		if python && (strings.HasPrefix(line, "dependencies") || strings.HasPrefix(line, "requires")) && strings.Contains(line, "[") {
+			inArray = true
+		}
+
+		if inArray {
+			for _, quoted := range quotedStrings(line) {
+				deps = append(deps, pythonPackageName(quoted))
+			}
+			if strings.Contains(line, "]") {
+				inArray = false
+			}
+			continue
+		}
+
+		if inTable {
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			// Optional dependency groups map a group name to an array of
+			// requirements rather than naming a dependency themselves.
+			if python && strings.Contains(value, "[") {
+				for _, quoted := range quotedStrings(value) {
+					deps = append(deps, pythonPackageName(quoted))
+				}
+				inArray = !strings.Contains(value, "]")
+				continue
+			}
+			deps = append(deps, strings.Trim(strings.TrimSpace(key), `"'`))
+		}
+	}
+
+	return deps
+}
+
+func pomDependencies(content []byte) []string {
+	type dependency struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+	}
+	var pom struct {
+		Parent       dependency   `xml:"parent"`
+		Dependencies []dependency `xml:"dependencies>dependency"`
+		Managed      []dependency `xml:"dependencyManagement>dependencies>dependency"`
+	}
+	if err := xml.Unmarshal(content, &pom); err != nil {
+		return nil
+	}
+
+	var deps []string
+	for _, dep := range append(append([]dependency{pom.Parent}, pom.Dependencies...), pom.Managed...) {
+		if dep.GroupID == "" {
+			continue
+		}
+		deps = append(deps, dep.GroupID+":"+dep.ArtifactID, dep.GroupID)
+	}
+	return deps
+}
+
+// pythonPackageName strips version specifiers, extras and markers from a
+// requirement, normalizing the name to lower case.
+func pythonPackageName(requirement string) string {
+	if i := strings.IndexAny(requirement, "=<>!~[;@ "); i >= 0 {
+		requirement = requirement[:i]
+	}
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(requirement), "_", "-"))
+}
+
+func quotedStrings(line string) []string {
+	var result []string
+	for {
+		start := strings.IndexAny(line, `"'`)
+		if start < 0 {
+			return result
+		}
+		end := strings.IndexByte(line[start+1:], line[start])
+		if end < 0 {
+			return result
+		}
+		result = append(result, line[start+1:start+1+end])
+		line = line[start+end+2:]
+	}
+}
+
+func stripComment(line, marker string) string {
+	if i := strings.Index(line, marker); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"math"
 	"sort"
 	"strings"
@@ -15,21 +16,62 @@ func NewRankingService() *RankingService {
 	return &RankingService{}
 }
 
-func (s *RankingService) RankRepositories(repos []model.Repository) []model.RankedRepository {
-	ranked := make([]model.RankedRepository, 0, len(repos))
+// RankRepositories scores the user's repositories and contributions together.
+// Repositories named in pinned (full names, in profile order) get a boost, and
+// are kept even if they are forks.
+// authorship, keyed by full name, holds the user's share of the commits of
+// those repositories it's known for. Since it's only fetched for the
+// strongest candidates, the rest are scored with the median factor of the
+// measured ones of their kind rather than assumed to be the user's alone.
+// Contributions to repositories already in repos, such as organization
+// repositories the user can access, are left out.
+func (s *RankingService) RankRepositories(repos []model.Repository, contributions []model.Contribution, pinned []string, authorship map[string]*model.Authorship) []model.RankedRepository {
+	ranked := make([]model.RankedRepository, 0, len(repos)+len(contributions))
+	listed := make(map[string]bool, len(repos))
+
+	repoNames := make([]string, len(repos))
+	for i, repo := range repos {
+		repoNames[i] = repo.FullName
+	}
+	repoFallback := medianAuthorshipFactor(repoNames, authorship, 1)
+
+	contributionNames := make([]string, len(contributions))
+	for i, contribution := range contributions {
+		contributionNames[i] = contribution.FullName
+	}
+	contributionFallback := medianAuthorshipFactor(contributionNames, authorship, 0.6)
 
 	for _, repo := range repos {
-		if repo.IsFork {
+		listed[strings.ToLower(repo.FullName)] = true
+		if repo.IsFork && !isPinned(repo.FullName, pinned) {
 			continue
 		}
 
-		score := s.calculateScore(repo)
-		highlights := s.generateHighlights(repo)
+		share := authorship[repo.FullName]
+		score := s.calculateScore(repo)*authorshipFactor(share, repoFallback) + pinnedBoost(repo.FullName, pinned)
+		highlights := append(s.generateAuthorshipHighlights(share), s.generateHighlights(repo)...)
 
 		ranked = append(ranked, model.RankedRepository{
 			Repository: repo,
 			Score:      score,
 			Highlights: highlights,
+			Authorship: share,
+		})
+	}
+
+	for i := range contributions {
+		contribution := &contributions[i]
+		if contribution.IsFork && !isPinned(contribution.FullName, pinned) || listed[strings.ToLower(contribution.FullName)] {
+			continue
+		}
+
+		share := authorship[contribution.FullName]
+		ranked = append(ranked, model.RankedRepository{
+			Repository:   contribution.Repository,
+			Score:        s.calculateContributionScore(*contribution, share, contributionFallback) + pinnedBoost(contribution.FullName, pinned),
+			Highlights:   append(s.generateAuthorshipHighlights(share), s.generateContributionHighlights(*contribution, share)...),
+			Contribution: contribution,
+			Authorship:   share,
 		})
 	}
 
@@ -67,6 +109,114 @@ func (s *RankingService) calculateScore(repo model.Repository) float64 {
 	return score
 }
 
+// pinnedBoost strongly favours repositories the user chose to showcase on
+// their profile, keeping the pinned order among them.
+func pinnedBoost(fullName string, pinned []string) float64 {
+	for i, name := range pinned {
+		if strings.EqualFold(name, fullName) {
+			return 20 + float64(len(pinned)-i)
+		}
+	}
+	return 0
+}
+
+// authorshipFactor scales a repository's score by the user's share of its
+// commits, from 0.4 for a negligible share up to 1 for sole authorship.
+// Without statistics it returns fallback.
+func authorshipFactor(authorship *model.Authorship, fallback float64) float64 {
+	if authorship == nil {
+		return fallback
+	}
+	return 0.4 + 0.6*authorship.Share
+}
+
+// medianAuthorshipFactor is the median authorshipFactor of those of the named
+// repositories authorship covers, or fallback if it covers none.
+func medianAuthorshipFactor(fullNames []string, authorship map[string]*model.Authorship, fallback float64) float64 {
+	var factors []float64
+	for _, name := range fullNames {
+		if share, ok := authorship[name]; ok && share != nil {
+			factors = append(factors, authorshipFactor(share, fallback))
+		}
+	}
+	if len(factors) == 0 {
+		return fallback
+	}
+
+	sort.Float64s(factors)
+	mid := len(factors) / 2
+	if len(factors)%2 == 0 {
+		return (factors[mid-1] + factors[mid]) / 2
+	}
+	return factors[mid]
+}
+
+// calculateContributionScore discounts the repository's own score, since the
+// user shares it with other maintainers, and adds their involvement on top.
+// fallback is the authorship factor used without statistics.
+func (s *RankingService) calculateContributionScore(contribution model.Contribution, authorship *model.Authorship, fallback float64) float64 {
+	repo := contribution.Repository
+	if contribution.LastContributedAt.After(repo.LastCommitDate) || repo.LastCommitDate.IsZero() {
+		repo.LastCommitDate = contribution.LastContributedAt
+	}
+
+	// Commit search only covers recent history, while the statistics span
+	// the whole default branch.
+	commits := contribution.CommitCount
+	if authorship != nil && authorship.Commits > commits {
+		commits = authorship.Commits
+	}
+
+	score := s.calculateScore(repo) * authorshipFactor(authorship, fallback)
+	score += math.Log1p(float64(commits)) * 2.0
+	score += math.Log1p(float64(contribution.MergedPullRequests)) * 3.0
+
+	return score
+}
+
+// generateAuthorshipHighlights describes the user's share of a repository
+// they didn't write alone.
+func (s *RankingService) generateAuthorshipHighlights(authorship *model.Authorship) []string {
+	if authorship == nil || authorship.Commits == 0 || authorship.Share >= 1 {
+		return nil
+	}
+
+	highlights := []string{fmt.Sprintf(
+		"Authored %d of %d commits (%.0f%%), %d lines added and %d removed",
+		authorship.Commits, authorship.TotalCommits, authorship.Share*100, authorship.Additions, authorship.Deletions,
+	)}
+
+	if !authorship.ActiveFrom.IsZero() {
+		from, to := authorship.ActiveFrom.Format("Jan 2006"), authorship.ActiveTo.Format("Jan 2006")
+		if from == to {
+			highlights = append(highlights, "Active in "+from)
+		} else {
+			highlights = append(highlights, fmt.Sprintf("Active from %s to %s", from, to))
+		}
+	}
+
+	return highlights
+}
+
+func (s *RankingService) generateContributionHighlights(contribution model.Contribution, authorship *model.Authorship) []string {
+	var highlights []string
+
+	// Authorship highlights already count the user's commits.
+	if contribution.CommitCount > 0 && (authorship == nil || authorship.Commits == 0) {
+		highlights = append(highlights, fmt.Sprintf("Contributed %d commits to %s", contribution.CommitCount, contribution.FullName))
+	}
+
+	if contribution.MergedPullRequests > 0 {
+		highlights = append(highlights, fmt.Sprintf("%d merged pull requests to %s", contribution.MergedPullRequests, contribution.FullName))
+	}
+
+	if contribution.Stars > 100 {
+		highlights = append(highlights, "Widely used project with community engagement")
+	}
+
+	return highlights
+}
+
 func (s *RankingService) generateHighlights(repo model.Repository) []string {
 	var highlights []string
 
@@ -85,6 +235,12 @@ func (s *RankingService) generateHighlights(repo model.Repository) []string {
 	return highlights
 }
 
+// generateReadmeHighlights turns the feature list of a sanitized README into
+// highlights.
+func (s *RankingService) generateReadmeHighlights(readme string) []string {
+	return readmeFeatures(readme, maxReadmeHighlights)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
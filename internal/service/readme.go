@@ -0,0 +1,195 @@
+package service
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxReadmeLength bounds the sanitized README kept for a project.
+	maxReadmeLength = 1500
+	// maxReadmeHighlights is how many README feature bullets become highlights.
+	maxReadmeHighlights  = 3
+	maxDescriptionLength = 200
+)
+
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	linkedImagePattern = regexp.MustCompile(`\[!\[[^\]]*\](\([^)]*\)|\[[^\]]*\])\](\([^)]*\)|\[[^\]]*\])`)
+	imagePattern       = regexp.MustCompile(`!\[[^\]]*\](\([^)]*\)|\[[^\]]*\])`)
+	linkPattern        = regexp.MustCompile(`\[([^\]]*)\](\([^)]*\)|\[[^\]]*\])`)
+	referencePattern   = regexp.MustCompile(`^\s*\[[^\]]+\]:\s`)
+	htmlTagPattern     = regexp.MustCompile(`<[^>]*>`)
+	emphasisPattern    = regexp.MustCompile("(\\*\\*|__|`)")
+	listItemPattern    = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.*)$`)
+	setextUnderline    = regexp.MustCompile(`^(=+|-+)$`)
+	sentenceEndPattern = regexp.MustCompile(`[.!?](\s|$)`)
+)
+
+// skippedReadmeSections are section titles, matched by prefix, whose content
+// is about using or working on a project rather than describing it.
+var skippedReadmeSections = []string{
+	"install", "getting started", "quick start", "quickstart", "usage", "build",
+	"requirements", "prerequisites", "setup", "configuration", "development",
+	"test", "contribut", "license", "changelog", "table of contents", "contents",
+	"credits", "acknowledg", "support", "sponsor", "authors", "faq", "roadmap",
+}
+
+// sanitizeReadme reduces a Markdown README to the plain text of its first
+// descriptive sections: badges, images, code blocks, HTML and link targets
+// are removed, and list items are kept one per line prefixed with "- ".
+// Paragraphs are separated by blank lines.
+func sanitizeReadme(raw string) string {
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	raw = htmlCommentPattern.ReplaceAllString(raw, "")
+
+	var blocks []string
+	var paragraph []string
+	var length int
+	skipping, inCode := false, false
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, strings.Join(paragraph, " "))
+			length += len(blocks[len(blocks)-1])
+			paragraph = nil
+		}
+	}
+
+	lines := strings.Split(raw, "\n")
+	for i := 0; i < len(lines) && length < maxReadmeLength; i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inCode = !inCode
+			flush()
+			continue
+		}
+		if inCode || referencePattern.MatchString(line) {
+			continue
+		}
+
+		// ATX headings, and setext headings underlined on the next line.
+		title, isHeading := "", false
+		if strings.HasPrefix(line, "#") {
+			title, isHeading = strings.TrimLeft(line, "# "), true
+		} else if line != "" && i+1 < len(lines) && setextUnderline.MatchString(strings.TrimSpace(lines[i+1])) && len(paragraph) == 0 {
+			title, isHeading = line, true
+			i++
+		}
+		if isHeading {
+			flush()
+			skipping = isSkippedSection(cleanMarkdown(title))
+			continue
+		}
+
+		if skipping {
+			continue
+		}
+
+		text := cleanMarkdown(line)
+		if !hasLetters(text) {
+			flush()
+			continue
+		}
+
+		if match := listItemPattern.FindStringSubmatch(text); match != nil {
+			flush()
+			blocks = append(blocks, "- "+match[1])
+			length += len(match[1])
+			continue
+		}
+
+		paragraph = append(paragraph, text)
+	}
+	flush()
+
+	result := strings.Join(blocks, "\n\n")
+	if len(result) > maxReadmeLength {
+		result = truncateText(result, maxReadmeLength)
+	}
+	return result
+}
+
+func cleanMarkdown(line string) string {
+	line = linkedImagePattern.ReplaceAllString(line, "")
+	line = imagePattern.ReplaceAllString(line, "")
+	line = linkPattern.ReplaceAllString(line, "$1")
+	line = htmlTagPattern.ReplaceAllString(line, "")
+	line = emphasisPattern.ReplaceAllString(line, "")
+	line = html.UnescapeString(line)
+	line = strings.TrimPrefix(line, "> ")
+	return strings.Join(strings.Fields(line), " ")
+}
+
+func isSkippedSection(title string) bool {
+	title = strings.ToLower(strings.TrimFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+	for _, prefix := range skippedReadmeSections {
+		if strings.HasPrefix(title, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLetters(text string) bool {
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 3
+}
+
+// readmeDescription returns the opening sentence of the first paragraph of a
+// sanitized README, for projects without a GitHub description.
+func readmeDescription(readme string) string {
+	for _, block := range strings.Split(readme, "\n\n") {
+		if strings.HasPrefix(block, "- ") || len(block) < 20 {
+			continue
+		}
+		if loc := sentenceEndPattern.FindStringIndex(block); loc != nil {
+			block = block[:loc[0]+1]
+		}
+		return truncateText(block, maxDescriptionLength)
+	}
+	return ""
+}
+
+// readmeFeatures returns the first list items of a sanitized README.
+func readmeFeatures(readme string, max int) []string {
+	var features []string
+	for _, block := range strings.Split(readme, "\n\n") {
+		if len(features) >= max {
+			break
+		}
+		item, ok := strings.CutPrefix(block, "- ")
+		if !ok || len(item) < 10 {
+			continue
+		}
+		features = append(features, truncateText(item, 120))
+	}
+	return features
+}
+
+// truncateText shortens text to at most max bytes at a word boundary.
+func truncateText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	cut := strings.LastIndexByte(text[:max-3], ' ')
+	if cut <= 0 {
+		// Without a space to break at, cut at the last whole rune.
+		cut = max - 3
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+	}
+	return strings.TrimRight(text[:cut], " ,;:") + "..."
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+	"github.com/yourusername/resume-builder/internal/repository"
+)
+
+const (
+	// refreshBatchSize bounds how many users a single refresh pass handles.
+	refreshBatchSize = 50
+	refreshTimeout   = 2 * time.Minute
+)
+
+// RefreshService periodically re-fetches the GitHub data of users whose
+// stored repositories have gone stale, and flags or regenerates their default
+// resume when it changed. Only the replica holding the advisory lock runs it.
+type RefreshService struct {
+	lock          *repository.AdvisoryLock
+	repoRepo      *repository.RepoRepository
+	resumeRepo    *repository.ResumeRepository
+	userRepo      *repository.UserRepository
+	authService   *AuthService
+	githubService *GitHubService
+	resumeService *ResumeService
+	interval      time.Duration
+	staleAfter    time.Duration
+	// regenerate replaces flagging stale resumes with generating new ones.
+	regenerate bool
+}
+
+func NewRefreshService(
+	lock *repository.AdvisoryLock,
+	repoRepo *repository.RepoRepository,
+	resumeRepo *repository.ResumeRepository,
+	userRepo *repository.UserRepository,
+	authService *AuthService,
+	githubService *GitHubService,
+	resumeService *ResumeService,
+	interval, staleAfter time.Duration,
+	regenerate bool,
+) *RefreshService {
+	return &RefreshService{
+		lock:          lock,
+		repoRepo:      repoRepo,
+		resumeRepo:    resumeRepo,
+		userRepo:      userRepo,
+		authService:   authService,
+		githubService: githubService,
+		resumeService: resumeService,
+		interval:      interval,
+		staleAfter:    staleAfter,
+		regenerate:    regenerate,
+	}
+}
+
+// Run refreshes stale users every interval until ctx is cancelled, whenever
+// this replica is or becomes the leader.
+func (s *RefreshService) Run(ctx context.Context) {
+	defer func() {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.lock.Release(releaseCtx); err != nil {
+			slog.Warn("failed to release refresh lock", "error", err)
+		}
+	}()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		leader, err := s.lock.TryAcquire(ctx)
+		if err != nil {
+			slog.Warn("failed to acquire refresh lock", "error", err)
+		} else if leader {
+			s.RefreshStale(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshStale runs one refresh pass over up to refreshBatchSize users whose
+// repositories were last fetched, or last attempted to be, more than
+// staleAfter ago. Users whose refresh fails are retried once staleAfter has
+// passed again, so they don't hold up everyone else.
+func (s *RefreshService) RefreshStale(ctx context.Context) {
+	userIDs, err := s.repoRepo.ListStaleUsers(ctx, time.Now().Add(-s.staleAfter), refreshBatchSize)
+	if err != nil {
+		slog.Error("failed to list stale users", "error", err)
+		return
+	}
+
+	var changed, failed int
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := s.repoRepo.RecordRefreshAttempt(ctx, userID, time.Now()); err != nil {
+			slog.Warn("failed to record refresh attempt", "error", err, "user_id", userID)
+		}
+
+		changes, err := s.refreshUser(ctx, userID)
+		if err != nil {
+			failed++
+			slog.Warn("failed to refresh github data", "error", err, "user_id", userID)
+			continue
+		}
+		if changes != nil {
+			changed++
+			slog.Info("github data changed",
+				"user_id", userID,
+				"since", changes.Since,
+				"added", changes.Added,
+				"removed", changes.Removed,
+				"pushed", changes.Pushed,
+				"stars_gained", changes.StarsGained,
+				"regenerated", s.regenerate,
+			)
+		}
+	}
+
+	if len(userIDs) > 0 {
+		slog.Info("refreshed stale users", "users", len(userIDs), "changed", changed, "failed", failed)
+	}
+}
+
+// refreshUser re-fetches the user's GitHub data and, if it changed, flags or
+// regenerates their default resume. It returns the changes, or nil if there
+// were none to act on.
+func (s *RefreshService) refreshUser(ctx context.Context, userID int64) (*model.GitHubChanges, error) {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	token, err := s.authService.GetUserToken(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github token: %w", err)
+	}
+
+	changes, err := s.githubService.RefreshUserData(ctx, userID, token, user.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch github data: %w", err)
+	}
+	if changes == nil || changes.Empty() {
+		return nil, nil
+	}
+
+	resume, err := s.resumeRepo.GetDefault(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if resume == nil {
+		return nil, nil
+	}
+
+	if !s.regenerate {
+		if err := s.resumeRepo.SetGitHubChanges(ctx, resume.ID, changes, true); err != nil {
+			return nil, fmt.Errorf("failed to flag resume %d: %w", resume.ID, err)
+		}
+		return changes, nil
+	}
+
+	regenerated, err := s.resumeService.RegenerateResume(ctx, resume, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to regenerate resume: %w", err)
+	}
+	if err := s.resumeRepo.SetGitHubChanges(ctx, regenerated.ID, changes, false); err != nil {
+		return nil, fmt.Errorf("failed to record changes on resume %d: %w", regenerated.ID, err)
+	}
+
+	return changes, nil
+}
@@ -3,26 +3,38 @@ package service
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 
 	"github.com/yourusername/resume-builder/internal/client"
 	"github.com/yourusername/resume-builder/internal/model"
 	"github.com/yourusername/resume-builder/internal/repository"
 )
 
+// UserLookup finds users by ID. It is implemented by
+// repository.UserRepository.
+type UserLookup interface {
+	GetByID(ctx context.Context, id int64) (*model.User, error)
+}
+
 type ResumeService struct {
 	resumeRepo     *repository.ResumeRepository
-	userRepo       *repository.UserRepository
+	userRepo       UserLookup
 	githubService  *GitHubService
 	rankingService *RankingService
 	llmClient      *client.LLMClient
+	// githubWebURL is the GitHub instance exported resumes link profiles on.
+	githubWebURL string
 }
 
 func NewResumeService(
 	resumeRepo *repository.ResumeRepository,
-	userRepo *repository.UserRepository,
+	userRepo UserLookup,
 	githubService *GitHubService,
 	rankingService *RankingService,
 	llmClient *client.LLMClient,
+	githubWebURL string,
 ) *ResumeService {
 	return &ResumeService{
 		resumeRepo:     resumeRepo,
@@ -30,41 +42,202 @@ func NewResumeService(
 		githubService:  githubService,
 		rankingService: rankingService,
 		llmClient:      llmClient,
+		githubWebURL:   githubWebURL,
+	}
+}
+
+// skillCandidateCount is how many of the top ranked repositories have their
+// language breakdown and manifests fetched to weight and extend skills.
+const skillCandidateCount = 10
+
+type GenerateOptions struct {
+	TargetRole string
+	// PinnedOnly restricts the projects to repositories pinned on the user's
+	// profile. It has no effect when nothing is pinned.
+	PinnedOnly bool
+	// Progress, if set, is called with each step of the generation.
+	Progress func(GenerationEvent)
+}
+
+func (o GenerateOptions) report(event GenerationEvent) {
+	if o.Progress != nil {
+		event.Progress = StageProgress(event.Stage)
+		o.Progress(event)
+	}
+}
+
+func (o GenerateOptions) reportStage(stage string) {
+	o.report(GenerationEvent{Type: EventStage, Stage: stage})
+}
+
+func (s *ResumeService) GenerateResume(ctx context.Context, userID int64, token string, opts GenerateOptions) (*model.Resume, error) {
+	resume, err := s.buildResume(ctx, userID, token, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.reportStage(StageSaving)
+	if err := s.resumeRepo.Create(ctx, resume); err != nil {
+		return nil, err
+	}
+
+	return resume, nil
+}
+
+// RegenerateResume rebuilds resume from the user's current GitHub data with
+// the options it was generated with, replacing its content in place. Its
+// title and default status are kept.
+func (s *ResumeService) RegenerateResume(ctx context.Context, resume *model.Resume, token string) (*model.Resume, error) {
+	regenerated, err := s.buildResume(ctx, resume.UserID, token, GenerateOptions{
+		TargetRole: resume.TargetRole,
+		PinnedOnly: resume.PinnedOnly,
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	regenerated.ID = resume.ID
+	regenerated.Title = resume.Title
+	regenerated.IsDefault = resume.IsDefault
+	regenerated.CreatedAt = resume.CreatedAt
+	if err := s.resumeRepo.Update(ctx, regenerated); err != nil {
+		return nil, err
+	}
+
+	return regenerated, nil
 }
 
-func (s *ResumeService) GenerateResume(ctx context.Context, userID int64, targetRole string, token string) (*model.Resume, error) {
-	_, repos, err := s.githubService.FetchUserData(ctx, token)
+// buildResume generates a resume from the user's GitHub data without saving
+// it.
+func (s *ResumeService) buildResume(ctx context.Context, userID int64, token string, opts GenerateOptions) (*model.Resume, error) {
+	targetRole := opts.TargetRole
+
+	opts.reportStage(StageFetchingProfile)
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	profile, repos, err := s.githubService.FetchUserData(ctx, userID, token, user.Username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch github data: %w", err)
 	}
+	opts.report(GenerationEvent{Type: EventProfileFetched, Stage: StageFetchingProfile, Login: profile.Login, RepoCount: len(repos)})
+
+	// Contributions only enrich the resume, so a failure here shouldn't
+	// block generation.
+	contributions, err := s.githubService.FetchContributions(ctx, token, profile.Login)
+	if err != nil {
+		slog.Warn("failed to fetch github contributions", "error", err, "user_id", userID)
+	}
+
+	opts.reportStage(StageRankingRepos)
+	candidateRepos, candidateContributions := repos, contributions
+	if opts.PinnedOnly && len(profile.PinnedRepositories) > 0 {
+		candidateRepos = filterPinned(repos, profile.PinnedRepositories)
+		candidateContributions = filterPinnedContributions(contributions, profile.PinnedRepositories)
+	}
 
-	rankedRepos := s.rankingService.RankRepositories(repos)
-	skills := s.githubService.ExtractSkills(repos)
+	rankedRepos := s.rankingService.RankRepositories(candidateRepos, candidateContributions, profile.PinnedRepositories, nil)
 
-	topProjects := s.selectTopProjects(rankedRepos, 5)
+	// Commit statistics of the strongest candidates show how much of each
+	// the user actually wrote, which can reorder them.
+	authorship, err := s.githubService.FetchAuthorship(ctx, token, profile.Login, rankedRepos[:min(skillCandidateCount, len(rankedRepos))])
+	if err != nil {
+		slog.Warn("failed to fetch repository authorship", "error", err, "user_id", userID)
+	} else if len(authorship) > 0 {
+		rankedRepos = s.rankingService.RankRepositories(candidateRepos, candidateContributions, profile.PinnedRepositories, authorship)
+	}
+
+	topRepositories := make([]string, 0, 5)
+	for _, repo := range rankedRepos[:min(5, len(rankedRepos))] {
+		topRepositories = append(topRepositories, repo.FullName)
+	}
+	opts.report(GenerationEvent{Type: EventRankingDone, Stage: StageRankingRepos, RepoCount: len(rankedRepos), TopRepositories: topRepositories})
+
+	// Skill weights and frameworks come from the language breakdown and
+	// manifests of the strongest candidates; without them skills are still
+	// listed from languages and topics.
+	opts.reportStage(StageExtractingSkills)
+	skillCandidates := rankedRepos[:min(skillCandidateCount, len(rankedRepos))]
+	if err := s.githubService.FetchLanguages(ctx, token, skillCandidates); err != nil {
+		slog.Warn("failed to fetch repository languages", "error", err, "user_id", userID)
+	}
+	if err := s.githubService.FetchFrameworks(ctx, token, skillCandidates); err != nil {
+		slog.Warn("failed to fetch repository manifests", "error", err, "user_id", userID)
+	}
+	skillSet := s.githubService.ExtractSkills(repos, skillCandidates)
+	skills := skillSet.Skills
+
+	// READMEs fill in what one-line descriptions leave out.
+	opts.reportStage(StageFetchingReadmes)
+	projectCandidates := rankedRepos[:min(5, len(rankedRepos))]
+	if err := s.githubService.FetchReadmes(ctx, token, projectCandidates); err != nil {
+		slog.Warn("failed to fetch repository readmes", "error", err, "user_id", userID)
+	}
+
+	opts.reportStage(StageEnhancingProjects)
+	topProjects := s.selectTopProjects(ctx, rankedRepos, 5, opts)
+	// Enhancements fall back to the plain descriptions when cancelled, so a
+	// cancellation only shows here.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Try LLM summary first, fallback to rule-based
+	opts.reportStage(StageWritingSummary)
 	summary, err := s.llmClient.GenerateSummary(ctx, targetRole, len(repos), skills)
 	if err != nil {
 		summary = s.generateSummary(targetRole, len(repos), skills)
 	}
+	opts.report(GenerationEvent{Type: EventSummaryWritten, Stage: StageWritingSummary, Summary: summary})
 
 	resume := &model.Resume{
-		UserID:     userID,
-		Title:      "GitHub Resume",
-		TargetRole: targetRole,
-		Summary:    summary,
-		Projects:   topProjects,
-		Skills:     skills,
-		IsDefault:  true,
+		UserID:       userID,
+		Title:        "GitHub Resume",
+		TargetRole:   targetRole,
+		Summary:      summary,
+		Projects:     topProjects,
+		Skills:       skills,
+		SkillWeights: skillSet.Weights,
+		SkillSources: skillSet.Sources,
+		IsDefault:    true,
+		PinnedOnly:   opts.PinnedOnly,
 	}
 
-	if err := s.resumeRepo.Create(ctx, resume); err != nil {
-		return nil, err
+	return resume, nil
+}
+
+func filterPinned(repos []model.Repository, pinned []string) []model.Repository {
+	var filtered []model.Repository
+	for _, repo := range repos {
+		if isPinned(repo.FullName, pinned) {
+			filtered = append(filtered, repo)
+		}
 	}
+	return filtered
+}
 
-	return resume, nil
+func filterPinnedContributions(contributions []model.Contribution, pinned []string) []model.Contribution {
+	var filtered []model.Contribution
+	for _, contribution := range contributions {
+		if isPinned(contribution.FullName, pinned) {
+			filtered = append(filtered, contribution)
+		}
+	}
+	return filtered
+}
+
+func isPinned(fullName string, pinned []string) bool {
+	for _, name := range pinned {
+		if strings.EqualFold(name, fullName) {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *ResumeService) GetResume(ctx context.Context, resumeID, userID int64) (*model.Resume, error) {
@@ -122,47 +295,96 @@ func (s *ResumeService) DeleteResume(ctx context.Context, resumeID, userID int64
 	return s.resumeRepo.Delete(ctx, resumeID)
 }
 
-func (s *ResumeService) selectTopProjects(rankedRepos []model.RankedRepository, count int) []model.ResumeProject {
+// maxConcurrentEnhancements bounds how many projects are enhanced by the LLM
+// at a time.
+const maxConcurrentEnhancements = 3
+
+// selectTopProjects builds the resume projects from the top count ranked
+// repositories, enhancing them concurrently. Projects are returned in rank
+// order whatever order their enhancements finish in.
+func (s *ResumeService) selectTopProjects(ctx context.Context, rankedRepos []model.RankedRepository, count int, opts GenerateOptions) []model.ResumeProject {
 	if len(rankedRepos) < count {
 		count = len(rankedRepos)
 	}
 
 	projects := make([]model.ResumeProject, count)
+	sem := make(chan struct{}, maxConcurrentEnhancements)
+	// Progress reports are serialized, as callers needn't expect concurrent
+	// calls.
+	var reportMu sync.Mutex
+
+	var wg sync.WaitGroup
 	for i := 0; i < count; i++ {
-		repo := rankedRepos[i]
-		
-		// Try to enhance with LLM
-		description := repo.Description
-		highlights := repo.Highlights
-		
-		if enhancedDesc, enhancedHighlights, err := s.llmClient.EnhanceProjectDescription(
-			context.Background(),
-			repo.Name,
-			repo.Description,
-			repo.Language,
-			repo.Topics,
-		); err == nil && enhancedDesc != "" {
-			description = enhancedDesc
-			if len(enhancedHighlights) > 0 {
-				highlights = enhancedHighlights
-			}
-		}
-		
-		projects[i] = model.ResumeProject{
-			RepoName:    repo.Name,
-			Description: description,
-			URL:         repo.URL,
-			Stars:       repo.Stars,
-			Language:    repo.Language,
-			Topics:      repo.Topics,
-			Highlights:  highlights,
-			Position:    i,
-		}
+		wg.Add(1)
+		go func(position int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			projects[position] = s.buildProject(ctx, rankedRepos[position], position)
+
+			reportMu.Lock()
+			defer reportMu.Unlock()
+			opts.report(GenerationEvent{Type: EventProjectEnhanced, Stage: StageEnhancingProjects, Project: &projects[position], ProjectCount: count})
+		}(i)
 	}
+	wg.Wait()
 
 	return projects
 }
 
+// buildProject turns a ranked repository into the resume project at
+// position, enhancing its description and highlights with the LLM when it's
+// available.
+func (s *ResumeService) buildProject(ctx context.Context, repo model.RankedRepository, position int) model.ResumeProject {
+	description := repo.Description
+	if description == "" {
+		description = readmeDescription(repo.Readme)
+	}
+
+	// README features describe the project, not the user's share of it.
+	highlights := repo.Highlights
+	if repo.Contribution == nil {
+		highlights = append(s.rankingService.generateReadmeHighlights(repo.Readme), highlights...)
+	}
+
+	// Try to enhance with LLM
+	if enhancedDesc, enhancedHighlights, err := s.llmClient.EnhanceProjectDescription(
+		ctx,
+		repo.Name,
+		description,
+		repo.Language,
+		repo.Topics,
+		repo.Readme,
+	); err == nil && enhancedDesc != "" {
+		description = enhancedDesc
+		// Keep the contribution highlights, which describe the user's
+		// share rather than the project as a whole.
+		if len(enhancedHighlights) > 0 && repo.Contribution == nil {
+			highlights = append(s.rankingService.generateAuthorshipHighlights(repo.Authorship), enhancedHighlights...)
+		}
+	}
+
+	// Repositories the user doesn't own are shown with their owner.
+	repoName := repo.Name
+	if repo.Contribution != nil {
+		repoName = repo.FullName
+	}
+
+	return model.ResumeProject{
+		RepoName:       repoName,
+		Description:    description,
+		URL:            repo.URL,
+		Stars:          repo.Stars,
+		Language:       repo.Language,
+		Topics:         repo.Topics,
+		Highlights:     highlights,
+		Position:       position,
+		IsContribution: repo.Contribution != nil,
+		Authorship:     repo.Authorship,
+	}
+}
+
 func (s *ResumeService) generateSummary(targetRole string, repoCount int, skills []string) string {
 	return fmt.Sprintf(
 		"Software engineer with %d public repositories. Experienced in %s. Seeking %s role.",
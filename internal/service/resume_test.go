@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/client"
+	"github.com/yourusername/resume-builder/internal/model"
+)
+
+type stubFetcher struct {
+	profile *model.GitHubProfile
+	repos   []model.Repository
+}
+
+func (f *stubFetcher) GetUserData(ctx context.Context, token, login string) (*model.GitHubProfile, []model.Repository, error) {
+	return f.profile, f.repos, nil
+}
+
+func (f *stubFetcher) GetContributions(ctx context.Context, token, login string) ([]model.Contribution, error) {
+	return nil, nil
+}
+
+func (f *stubFetcher) GetLanguages(ctx context.Context, token, fullName string) (map[string]int, error) {
+	for _, repo := range f.repos {
+		if repo.FullName == fullName && repo.Language != "" {
+			return map[string]int{repo.Language: 1000}, nil
+		}
+	}
+	return map[string]int{}, nil
+}
+
+func (f *stubFetcher) GetManifests(ctx context.Context, token, fullName string, paths []string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func (f *stubFetcher) GetReadme(ctx context.Context, token, fullName string) (string, error) {
+	return "", nil
+}
+
+func (f *stubFetcher) GetAuthorship(ctx context.Context, token, fullName, login string) (*model.Authorship, error) {
+	return nil, client.ErrStatsPending
+}
+
+type stubSnapshots struct{}
+
+func (stubSnapshots) Save(ctx context.Context, login string, profile *model.GitHubProfile, repos []model.Repository) error {
+	return nil
+}
+
+func (stubSnapshots) GetByLogin(ctx context.Context, login string) (*model.GitHubSnapshot, error) {
+	return nil, nil
+}
+
+type stubFetches struct{}
+
+func (stubFetches) SaveFetch(ctx context.Context, userID int64, repos []model.Repository, fetchedAt time.Time) error {
+	return nil
+}
+
+func (stubFetches) GetLatest(ctx context.Context, userID int64) ([]model.StoredRepository, error) {
+	return nil, nil
+}
+
+func (stubFetches) PruneFetches(ctx context.Context, userID int64, keep int) error {
+	return nil
+}
+
+type stubUsers struct{}
+
+func (stubUsers) GetByID(ctx context.Context, id int64) (*model.User, error) {
+	return &model.User{ID: id, Username: "octocat", Name: "Mona Octocat"}, nil
+}
+
+// newTestResumeService builds a ResumeService on stubbed GitHub data and
+// storage, so only the LLM provider varies between tests.
+func newTestResumeService(provider client.LLMProvider) *ResumeService {
+	fetcher := &stubFetcher{
+		profile: &model.GitHubProfile{Login: "octocat", Name: "Mona Octocat"},
+		repos: []model.Repository{
+			{
+				Name:           "api",
+				FullName:       "octocat/api",
+				Description:    "HTTP API for resumes",
+				URL:            "https://github.com/octocat/api",
+				Stars:          40,
+				Language:       "Go",
+				Topics:         []string{"api", "golang"},
+				LastCommitDate: time.Now().Add(-24 * time.Hour),
+			},
+			{
+				Name:           "tool",
+				FullName:       "octocat/tool",
+				URL:            "https://github.com/octocat/tool",
+				Stars:          3,
+				Language:       "Python",
+				LastCommitDate: time.Now().Add(-30 * 24 * time.Hour),
+			},
+		},
+	}
+
+	cache := client.NewCacheClient("", "", 0, false)
+	githubService := NewGitHubService(fetcher, cache, stubSnapshots{}, stubFetches{}, 0)
+	llmClient := client.NewLLMClient(provider, true)
+
+	return NewResumeService(nil, stubUsers{}, githubService, NewRankingService(), llmClient, "https://github.com")
+}
+
+func projectByName(t *testing.T, resume *model.Resume, name string) model.ResumeProject {
+	t.Helper()
+	for _, project := range resume.Projects {
+		if project.RepoName == name {
+			return project
+		}
+	}
+	t.Fatalf("resume has no project %q", name)
+	return model.ResumeProject{}
+}
+
+func TestBuildResumeWithFakeProvider(t *testing.T) {
+	s := newTestResumeService(client.NewFakeProvider())
+
+	resume, err := s.buildResume(context.Background(), 1, "token", GenerateOptions{TargetRole: "Backend Engineer"})
+	if err != nil {
+		t.Fatalf("buildResume: %v", err)
+	}
+
+	if !strings.HasPrefix(resume.Summary, "Backend Engineer candidate with 2 GitHub repositories.") {
+		t.Errorf("Summary = %q, want the fake provider's summary", resume.Summary)
+	}
+	if len(resume.Projects) != 2 {
+		t.Fatalf("got %d projects, want 2", len(resume.Projects))
+	}
+
+	api := projectByName(t, resume, "api")
+	if api.Description != "HTTP API for resumes." {
+		t.Errorf("api description = %q", api.Description)
+	}
+	if len(api.Highlights) == 0 || api.Highlights[0] != "Built api in Go" {
+		t.Errorf("api highlights = %q", api.Highlights)
+	}
+
+	tool := projectByName(t, resume, "tool")
+	if tool.Description != "A Python project." {
+		t.Errorf("tool description = %q", tool.Description)
+	}
+}
+
+func TestBuildResumeReplaysFixtures(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	modelID := client.LLMModelID("fake", "")
+	opts := GenerateOptions{TargetRole: "Backend Engineer"}
+
+	recorded, err := newTestResumeService(client.NewFixtureProvider(dir, modelID, client.NewFakeProvider())).buildResume(ctx, 1, "token", opts)
+	if err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("reading fixtures: %v", err)
+	}
+	// One summary and one enhancement per project.
+	if len(entries) != 3 {
+		t.Fatalf("recorded %d fixtures, want 3", len(entries))
+	}
+
+	replayed, err := newTestResumeService(client.NewFixtureProvider(dir, modelID, nil)).buildResume(ctx, 1, "token", opts)
+	if err != nil {
+		t.Fatalf("replaying: %v", err)
+	}
+
+	if !strings.HasPrefix(replayed.Summary, "Backend Engineer candidate") {
+		t.Errorf("replayed Summary = %q, want the recorded one", replayed.Summary)
+	}
+	if replayed.Summary != recorded.Summary {
+		t.Errorf("replayed Summary = %q, want %q", replayed.Summary, recorded.Summary)
+	}
+	if !reflect.DeepEqual(replayed.Projects, recorded.Projects) {
+		t.Errorf("replayed Projects = %+v, want %+v", replayed.Projects, recorded.Projects)
+	}
+}
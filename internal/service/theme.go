@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/resume-builder/internal/export"
+	"github.com/yourusername/resume-builder/internal/model"
+	"github.com/yourusername/resume-builder/internal/repository"
+)
+
+var ErrThemeNotFound = errors.New("theme not found")
+
+type ThemeService struct {
+	themeRepo *repository.ThemeRepository
+	// githubWebURL is the GitHub instance rendered resumes link profiles on.
+	githubWebURL string
+}
+
+func NewThemeService(themeRepo *repository.ThemeRepository, githubWebURL string) *ThemeService {
+	return &ThemeService{themeRepo: themeRepo, githubWebURL: githubWebURL}
+}
+
+// SaveTheme validates an uploaded theme and stores it for the user,
+// replacing any existing theme with the same name.
+func (s *ThemeService) SaveTheme(ctx context.Context, userID int64, name, source string) (*model.Theme, error) {
+	if !export.IsValidThemeName(name) {
+		return nil, fmt.Errorf("%w: name must be lowercase letters, digits and dashes and not a built-in theme", export.ErrInvalidTheme)
+	}
+
+	if _, err := export.ParseTheme(name, source); err != nil {
+		return nil, err
+	}
+
+	theme := &model.Theme{
+		UserID: userID,
+		Name:   name,
+		Source: source,
+	}
+
+	if err := s.themeRepo.Upsert(ctx, theme); err != nil {
+		return nil, err
+	}
+
+	return theme, nil
+}
+
+func (s *ThemeService) ListThemes(ctx context.Context, userID int64) ([]model.Theme, error) {
+	return s.themeRepo.ListByUserID(ctx, userID)
+}
+
+func (s *ThemeService) DeleteTheme(ctx context.Context, userID int64, name string) error {
+	deleted, err := s.themeRepo.Delete(ctx, userID, name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrThemeNotFound
+	}
+	return nil
+}
+
+// RenderResume renders a resume with a built-in theme or one the user uploaded.
+func (s *ThemeService) RenderResume(ctx context.Context, resume *model.Resume, user *model.User, themeName string) ([]byte, error) {
+	if themeName == "" {
+		themeName = export.DefaultTheme
+	}
+
+	if tmpl, ok := export.BuiltinTheme(themeName); ok {
+		return export.HTML(resume, user, s.githubWebURL, tmpl)
+	}
+
+	theme, err := s.themeRepo.GetByName(ctx, user.ID, themeName)
+	if err != nil {
+		return nil, err
+	}
+	if theme == nil {
+		return nil, fmt.Errorf("%w: %s", export.ErrUnknownTemplate, themeName)
+	}
+
+	tmpl, err := export.ParseTheme(theme.Name, theme.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	return export.HTML(resume, user, s.githubWebURL, tmpl)
+}
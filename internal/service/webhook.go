@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/resume-builder/internal/model"
+	"github.com/yourusername/resume-builder/internal/repository"
+)
+
+var ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+
+// WebhookService applies GitHub webhook deliveries to the repository
+// snapshots of the users who own the repositories involved.
+type WebhookService struct {
+	snapshots *repository.SnapshotRepository
+}
+
+func NewWebhookService(snapshots *repository.SnapshotRepository) *WebhookService {
+	return &WebhookService{snapshots: snapshots}
+}
+
+type webhookPayload struct {
+	Action     string             `json:"action"`
+	Repository *webhookRepository `json:"repository"`
+	Changes    struct {
+		Repository struct {
+			Name struct {
+				From string `json:"from"`
+			} `json:"name"`
+		} `json:"repository"`
+		Owner struct {
+			From struct {
+				User *struct {
+					Login string `json:"login"`
+				} `json:"user"`
+				Organization *struct {
+					Login string `json:"login"`
+				} `json:"organization"`
+			} `json:"from"`
+		} `json:"owner"`
+	} `json:"changes"`
+}
+
+type webhookRepository struct {
+	Name        string      `json:"name"`
+	FullName    string      `json:"full_name"`
+	Description string      `json:"description"`
+	HTMLURL     string      `json:"html_url"`
+	Stars       int         `json:"stargazers_count"`
+	Forks       int         `json:"forks_count"`
+	Language    string      `json:"language"`
+	Topics      []string    `json:"topics"`
+	Private     bool        `json:"private"`
+	Fork        bool        `json:"fork"`
+	CreatedAt   webhookTime `json:"created_at"`
+	UpdatedAt   webhookTime `json:"updated_at"`
+	PushedAt    webhookTime `json:"pushed_at"`
+	Owner       struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+// webhookTime accepts both timestamp forms GitHub uses in webhooks: push
+// events give Unix seconds, other events RFC 3339 strings.
+type webhookTime time.Time
+
+func (t *webhookTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if seconds, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		*t = webhookTime(time.Unix(seconds, 0).UTC())
+		return nil
+	}
+
+	var parsed time.Time
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+	*t = webhookTime(parsed)
+	return nil
+}
+
+// HandleEvent applies a push, repository, star or release event to the
+// snapshots of the repository's owner and of everyone else whose snapshot
+// holds it, like collaborators and organization members. Other events, and
+// events no snapshot is affected by, are ignored.
+func (s *WebhookService) HandleEvent(ctx context.Context, event string, payload []byte) error {
+	switch event {
+	case "push", "repository", "star", "release":
+	default:
+		return nil
+	}
+
+	var p webhookPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+	if p.Repository == nil || p.Repository.FullName == "" {
+		return fmt.Errorf("%w: missing repository", ErrInvalidWebhookPayload)
+	}
+
+	repo := p.Repository
+	owner := repo.Owner.Login
+
+	if event == "repository" {
+		switch p.Action {
+		case "deleted":
+			return s.update(ctx, owner, repo.FullName, func(snapshot *model.GitHubSnapshot) {
+				removeRepository(snapshot, repo.FullName)
+			})
+		case "renamed":
+			oldName := owner + "/" + p.Changes.Repository.Name.From
+			return s.update(ctx, owner, oldName, func(snapshot *model.GitHubSnapshot) {
+				removeRepository(snapshot, oldName)
+				upsertRepository(snapshot, repo)
+			})
+		case "transferred":
+			previous := p.Changes.Owner.From
+			var previousOwner string
+			if previous.User != nil {
+				previousOwner = previous.User.Login
+			} else if previous.Organization != nil {
+				previousOwner = previous.Organization.Login
+			}
+			if previousOwner != "" {
+				oldName := previousOwner + "/" + repo.Name
+				err := s.update(ctx, previousOwner, oldName, func(snapshot *model.GitHubSnapshot) {
+					removeRepository(snapshot, oldName)
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return s.update(ctx, owner, repo.FullName, func(snapshot *model.GitHubSnapshot) {
+		upsertRepository(snapshot, repo)
+	})
+}
+
+func (s *WebhookService) update(ctx context.Context, login, fullName string, fn func(*model.GitHubSnapshot)) error {
+	if _, err := s.snapshots.Update(ctx, login, fullName, fn); err != nil {
+		return fmt.Errorf("failed to update snapshots of %s: %w", fullName, err)
+	}
+	return nil
+}
+
+func removeRepository(snapshot *model.GitHubSnapshot, fullName string) {
+	repos := snapshot.Repositories[:0]
+	for _, repo := range snapshot.Repositories {
+		if !strings.EqualFold(repo.FullName, fullName) {
+			repos = append(repos, repo)
+		}
+	}
+	snapshot.Repositories = repos
+}
+
+// upsertRepository refreshes a repository in the snapshot from a webhook
+// payload, keeping what payloads don't carry, like the language breakdown.
+func upsertRepository(snapshot *model.GitHubSnapshot, r *webhookRepository) {
+	updated := model.Repository{
+		Name:           r.Name,
+		FullName:       r.FullName,
+		Description:    r.Description,
+		URL:            r.HTMLURL,
+		Stars:          r.Stars,
+		Forks:          r.Forks,
+		Language:       r.Language,
+		Topics:         r.Topics,
+		LastCommitDate: time.Time(r.PushedAt),
+		CreatedAt:      time.Time(r.CreatedAt),
+		UpdatedAt:      time.Time(r.UpdatedAt),
+		IsPrivate:      r.Private,
+		IsFork:         r.Fork,
+	}
+	if updated.Topics == nil {
+		updated.Topics = []string{}
+	}
+
+	for i, repo := range snapshot.Repositories {
+		if strings.EqualFold(repo.FullName, r.FullName) {
+			updated.Languages = repo.Languages
+			snapshot.Repositories[i] = updated
+			return
+		}
+	}
+
+	snapshot.Repositories = append([]model.Repository{updated}, snapshot.Repositories...)
+}
@@ -0,0 +1 @@
+ALTER TABLE resumes DROP COLUMN IF EXISTS skill_sources;
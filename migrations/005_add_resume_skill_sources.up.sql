@@ -0,0 +1 @@
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS skill_sources JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS github_snapshots (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    profile JSONB NOT NULL,
+    repositories JSONB NOT NULL DEFAULT '[]',
+    fetched_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_username_lower ON users(LOWER(username));
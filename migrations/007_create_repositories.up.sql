@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS repositories (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    description TEXT,
+    url TEXT NOT NULL,
+    stars INTEGER NOT NULL DEFAULT 0,
+    forks INTEGER NOT NULL DEFAULT 0,
+    language VARCHAR(100),
+    topics TEXT[] NOT NULL DEFAULT '{}',
+    languages JSONB NOT NULL DEFAULT '{}',
+    is_private BOOLEAN NOT NULL DEFAULT false,
+    is_fork BOOLEAN NOT NULL DEFAULT false,
+    last_commit_date TIMESTAMP,
+    repo_created_at TIMESTAMP,
+    repo_updated_at TIMESTAMP,
+    fetched_at TIMESTAMP NOT NULL,
+    UNIQUE (user_id, full_name, fetched_at)
+);
+
+CREATE INDEX idx_repositories_user_fetched_at ON repositories(user_id, fetched_at DESC);
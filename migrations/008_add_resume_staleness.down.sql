@@ -0,0 +1,2 @@
+ALTER TABLE resumes DROP COLUMN IF EXISTS github_changes;
+ALTER TABLE resumes DROP COLUMN IF EXISTS stale_since;
@@ -0,0 +1,2 @@
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS stale_since TIMESTAMP;
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS github_changes JSONB;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS generation_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    stage VARCHAR(50) NOT NULL DEFAULT '',
+    progress INTEGER NOT NULL DEFAULT 0,
+    target_role VARCHAR(255),
+    pinned_only BOOLEAN NOT NULL DEFAULT false,
+    resume_id BIGINT REFERENCES resumes(id) ON DELETE SET NULL,
+    error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_generation_jobs_user_id ON generation_jobs(user_id);
+CREATE INDEX idx_generation_jobs_pending ON generation_jobs(created_at) WHERE status IN ('queued', 'running');
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_github_snapshots_repositories;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_github_snapshots_repositories ON github_snapshots USING GIN (repositories jsonb_path_ops);
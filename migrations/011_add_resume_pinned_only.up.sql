@@ -0,0 +1 @@
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS pinned_only BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,10 @@
+-- Keep only the oldest pending job of each user before allowing one at most.
+UPDATE generation_jobs j
+SET status = 'failed', error = 'superseded by an earlier generation', updated_at = NOW(), finished_at = NOW()
+WHERE status IN ('queued', 'running')
+    AND EXISTS (
+        SELECT 1 FROM generation_jobs o
+        WHERE o.user_id = j.user_id AND o.status IN ('queued', 'running') AND o.id < j.id
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_generation_jobs_user_pending ON generation_jobs(user_id) WHERE status IN ('queued', 'running');
@@ -0,0 +1 @@
+ALTER TABLE generation_jobs DROP COLUMN IF EXISTS retry_at;
@@ -0,0 +1 @@
+ALTER TABLE generation_jobs ADD COLUMN IF NOT EXISTS retry_at TIMESTAMP;